@@ -3,7 +3,6 @@ package api
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -14,7 +13,7 @@ func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) boo
 	if contentType := r.Header.Get("Content-Type"); contentType != "" {
 		if contentType != "application/json" {
 			msg := "Content-Type header is not application/json"
-			http.Error(w, msg, http.StatusUnsupportedMediaType)
+			sendError(w, r, http.StatusUnsupportedMediaType, errors.New(msg))
 			return false
 		}
 	}
@@ -31,29 +30,29 @@ func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) boo
 
 		switch {
 		case errors.As(err, &syntaxError):
-			msg := fmt.Sprintf("Request body contains badly-formed JSON (at position %d)", syntaxError.Offset)
-			http.Error(w, msg, http.StatusBadRequest)
+			err := newRequestError(CodeMalformedJSON, "", "Request body contains badly-formed JSON (at position %d)", syntaxError.Offset)
+			sendError(w, r, http.StatusBadRequest, err)
 		// In some circumstances Decode() may also return an
 		// io.ErrUnexpectedEOF error for syntax errors in the JSON.
 		case errors.Is(err, io.ErrUnexpectedEOF):
-			msg := fmt.Sprintf("Request body contains badly-formed JSON")
-			http.Error(w, msg, http.StatusBadRequest)
+			err := newRequestError(CodeMalformedJSON, "", "Request body contains badly-formed JSON")
+			sendError(w, r, http.StatusBadRequest, err)
 		case errors.As(err, &unmarshalTypeError):
-			msg := fmt.Sprintf("Request body contains an invalid value for the %q field (at position %d)", unmarshalTypeError.Field, unmarshalTypeError.Offset)
-			http.Error(w, msg, http.StatusBadRequest)
+			err := newRequestError(CodeInvalidField, unmarshalTypeError.Field, "Request body contains an invalid value for the %q field (at position %d)", unmarshalTypeError.Field, unmarshalTypeError.Offset)
+			sendError(w, r, http.StatusBadRequest, err)
 		case strings.HasPrefix(err.Error(), "json: unknown field "):
 			fieldName := strings.TrimPrefix(err.Error(), "json: unknown field ")
-			msg := fmt.Sprintf("Request body contains unknown field %s", fieldName)
-			http.Error(w, msg, http.StatusBadRequest)
+			err := newRequestError(CodeUnknownField, strings.Trim(fieldName, `"`), "Request body contains unknown field %s", fieldName)
+			sendError(w, r, http.StatusBadRequest, err)
 		case errors.Is(err, io.EOF):
-			msg := "Request body must not be empty"
-			http.Error(w, msg, http.StatusBadRequest)
+			err := newRequestError(CodeEmptyBody, "", "Request body must not be empty")
+			sendError(w, r, http.StatusBadRequest, err)
 		case err.Error() == "http: request body too large":
 			msg := "Request body must not be larger than 1MB"
-			http.Error(w, msg, http.StatusRequestEntityTooLarge)
+			sendError(w, r, http.StatusRequestEntityTooLarge, errors.New(msg))
 		default:
 			log.Println(err.Error())
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			sendError(w, r, http.StatusInternalServerError, errors.New(http.StatusText(http.StatusInternalServerError)))
 		}
 
 		return false
@@ -61,20 +60,30 @@ func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) boo
 
 	err = dec.Decode(&struct{}{})
 	if err != io.EOF {
-		msg := "Request body must only contain a single JSON object"
-		http.Error(w, msg, http.StatusBadRequest)
+		err := newRequestError(CodeMalformedJSON, "", "Request body must only contain a single JSON object")
+		sendError(w, r, http.StatusBadRequest, err)
 		return false
 	}
 
 	return true
 }
 
-func sendJSONResponse(w http.ResponseWriter, response interface{}) {
+func sendJSONResponse(w http.ResponseWriter, r *http.Request, response interface{}) {
+	sendJSONStatus(w, r, http.StatusOK, response)
+}
+
+// sendJSONStatus responds with status and response as JSON. Any other headers have to be set
+// before, since they can't change once the status is written
+func sendJSONStatus(w http.ResponseWriter, r *http.Request, status int, response interface{}) {
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		// The error can say what is inside the response, so it is only for the server's logs
+		log.Println(err.Error())
+		sendError(w, r, http.StatusInternalServerError, errors.New(http.StatusText(http.StatusInternalServerError)))
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	w.Write(jsonResponse)
 }
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// ErrorCode is a stable, machine readable code for what went wrong with a request. Messages
+// are for people and may change, but codes don't
+type ErrorCode string
+
+// Error codes the API responds with
+const (
+	CodeInvalidRequest       ErrorCode = "invalid_request"
+	CodeMethodNotAllowed     ErrorCode = "method_not_allowed"
+	CodeUnsupportedMediaType ErrorCode = "unsupported_media_type"
+	CodeMalformedJSON        ErrorCode = "malformed_json"
+	CodeInvalidField         ErrorCode = "invalid_field"
+	CodeUnknownField         ErrorCode = "unknown_field"
+	CodeEmptyBody            ErrorCode = "empty_body"
+	CodeBodyTooLarge         ErrorCode = "body_too_large"
+	CodeNotFound             ErrorCode = "not_found"
+	CodeGameNotFound         ErrorCode = "game_not_found"
+	CodeGameClosed           ErrorCode = "game_closed"
+	CodeTournamentNotFound   ErrorCode = "tournament_not_found"
+	CodeWrongPassphrase      ErrorCode = "wrong_passphrase"
+	CodeNotHost              ErrorCode = "not_host"
+	CodeInvalidInvite        ErrorCode = "invalid_invite"
+	CodeSpectatingDisabled   ErrorCode = "spectating_disabled"
+	CodeSpectatorNotFound    ErrorCode = "spectator_not_found"
+	CodePlayerNotFound       ErrorCode = "player_not_found"
+	CodeEntrantNotFound      ErrorCode = "entrant_not_found"
+	CodeSeatTaken            ErrorCode = "seat_taken"
+	CodeGameFull             ErrorCode = "game_full"
+	CodeInvalidSeat          ErrorCode = "invalid_seat"
+	CodeWrongClaim           ErrorCode = "wrong_claim"
+	CodeConflict             ErrorCode = "conflict"
+	CodeInternal             ErrorCode = "internal_error"
+)
+
+// requestIDHeader is where clients can pass their own id for a request, and where the id is
+// echoed back on errors
+const requestIDHeader = "X-Request-ID"
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	// Field is the request field which was invalid, when the error is about one
+	Field string `json:"field,omitempty"`
+	// RequestID identifies the request in the server's logs
+	RequestID string `json:"requestID"`
+}
+
+// requestError is an error from the api layer which knows its code
+type requestError struct {
+	code    ErrorCode
+	field   string
+	message string
+}
+
+func (err *requestError) Error() string {
+	return err.message
+}
+
+func newRequestError(code ErrorCode, field string, format string, args ...interface{}) error {
+	return &requestError{code: code, field: field, message: fmt.Sprintf(format, args...)}
+}
+
+// pokerErrors are the statuses and codes for the errors the poker package lets callers tell
+// apart
+var pokerErrors = []struct {
+	err    error
+	status int
+	code   ErrorCode
+	field  string
+}{
+	{poker.ErrSeatTaken, http.StatusConflict, CodeSeatTaken, ""},
+	{poker.ErrGameFull, http.StatusConflict, CodeGameFull, ""},
+	{poker.ErrInvalidSeat, http.StatusBadRequest, CodeInvalidSeat, ""},
+	{poker.ErrWrongClaim, http.StatusForbidden, CodeWrongClaim, ""},
+	{poker.ErrInvalidAction, http.StatusBadRequest, CodeInvalidField, "action"},
+	{poker.ErrSpectatingDisabled, http.StatusForbidden, CodeSpectatingDisabled, ""},
+	{poker.ErrNotSpectating, http.StatusForbidden, CodeSpectatorNotFound, ""},
+}
+
+// statusCodes are the codes for errors with nothing more specific to go on than their status
+var statusCodes = map[int]ErrorCode{
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusRequestEntityTooLarge: CodeBodyTooLarge,
+	http.StatusInternalServerError:   CodeInternal,
+}
+
+// sendError responds with err in the error envelope and status. The poker package's typed
+// errors have their own status, which is used instead
+func sendError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	response := ErrorResponse{Code: CodeInvalidRequest, Message: err.Error(), RequestID: requestID(r)}
+	if code, ok := statusCodes[status]; ok {
+		response.Code = code
+	}
+
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		response.Code = reqErr.code
+		response.Field = reqErr.field
+	}
+	for _, known := range pokerErrors {
+		if errors.Is(err, known.err) {
+			status = known.status
+			response.Code = known.code
+			response.Field = known.field
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(requestIDHeader, response.RequestID)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// methodNotAllowed rejects a request which doesn't use the one method the endpoint takes
+func methodNotAllowed(w http.ResponseWriter, r *http.Request, method string) {
+	w.Header().Set("Allow", method)
+	sendError(w, r, http.StatusMethodNotAllowed, fmt.Errorf("Must be %s", method))
+}
+
+// requestID gets the id of the request from its header, or makes up a random one
+func requestID(r *http.Request) string {
+	if id := r.Header.Get(requestIDHeader); id != "" {
+		return id
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorResponses(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	playerReq := AddPlayerRequest{Name: "foo", Seat: 3, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+	serveTestRequest(gameManager.AddPlayer, playerReq)
+
+	tests := []struct {
+		body   interface{}
+		status int
+		code   ErrorCode
+		field  string
+	}{
+		{playerReq, http.StatusConflict, CodeSeatTaken, ""},
+		{AddPlayerRequest{GameID: gameResponse.GameID, Seat: 9, Passphrase: "foobar"}, http.StatusBadRequest, CodeInvalidSeat, ""},
+		{AddPlayerRequest{GameID: gameResponse.GameID, Seat: 4, Passphrase: "wrong"}, http.StatusBadRequest, CodeGameNotFound, "gameID"},
+		{AddPlayerRequest{GameID: "nothing", Passphrase: "foobar"}, http.StatusBadRequest, CodeGameNotFound, "gameID"},
+		{map[string]interface{}{"seat": "four"}, http.StatusBadRequest, CodeInvalidField, "seat"},
+		{map[string]interface{}{"table": 4}, http.StatusBadRequest, CodeUnknownField, "table"},
+	}
+	for _, test := range tests {
+		recorder := serveTestRequest(gameManager.AddPlayer, test.body)
+		response := ErrorResponse{}
+		readResponse(recorder.Result(), &response)
+		if recorder.Code != test.status || response.Code != test.code || response.Field != test.field {
+			t.Error(test.body, recorder.Code, response)
+		}
+		if response.Message == "" || response.RequestID == "" || recorder.Header().Get(requestIDHeader) != response.RequestID {
+			t.Error(response)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/stub-uri", bytes.NewReader(nil))
+	req.Header.Set(requestIDHeader, "abc123")
+	recorder := httptest.NewRecorder()
+	gameManager.AddPlayer(recorder, req)
+	response := ErrorResponse{}
+	readResponse(recorder.Result(), &response)
+	if recorder.Code != http.StatusMethodNotAllowed || response.Code != CodeMethodNotAllowed || response.RequestID != "abc123" {
+		t.Error(response)
+	}
+}
+
+func TestSecretsArentSeats(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+	for seat := 0; seat < 2; seat++ {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		serveTestRequest(gameManager.AddPlayer, playerReq)
+	}
+
+	// Knowing the passphrase isn't enough to act for someone else
+	handReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 1, Secret: -1}
+	if recorder := serveTestRequest(gameManager.StartHand, handReq); recorder.Code != http.StatusForbidden {
+		t.Error(recorder.Code)
+	}
+}
+
+func TestJSONResponses(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	if recorder := serveTestRequest(gameManager.CreateGame, gameReq); recorder.Header().Get("Content-Type") != "application/json" {
+		t.Error(recorder.Header())
+	}
+
+	// Channels can't be marshalled, which still gets the error envelope without saying why
+	req := httptest.NewRequest("POST", "/stub-uri", bytes.NewReader(nil))
+	recorder := httptest.NewRecorder()
+	sendJSONResponse(recorder, req, make(chan int))
+	response := ErrorResponse{}
+	readResponse(recorder.Result(), &response)
+	if recorder.Code != http.StatusInternalServerError || response.Code != CodeInternal || recorder.Header().Get("Content-Type") != "application/json" {
+		t.Error(recorder.Code, response)
+	}
+	if strings.Contains(response.Message, "json") || strings.Contains(response.Message, "chan") {
+		t.Error("The marshal error stays on the server", response.Message)
+	}
+}
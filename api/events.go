@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// event is a message pushed to everyone following a game
+type event struct {
+	Name string
+	Data interface{}
+}
+
+// eventHub hands out the events of each game to its subscribers. It has its own lock since
+// every game's loop publishes to it
+type eventHub struct {
+	sync.Mutex
+	subscribers map[string]map[chan event]bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: make(map[string]map[chan event]bool)}
+}
+
+func (hub *eventHub) subscribe(gameID string) chan event {
+	hub.Lock()
+	defer hub.Unlock()
+
+	if _, ok := hub.subscribers[gameID]; !ok {
+		hub.subscribers[gameID] = make(map[chan event]bool)
+	}
+	events := make(chan event, 16)
+	hub.subscribers[gameID][events] = true
+	return events
+}
+
+func (hub *eventHub) unsubscribe(gameID string, events chan event) {
+	hub.Lock()
+	defer hub.Unlock()
+
+	delete(hub.subscribers[gameID], events)
+	if len(hub.subscribers[gameID]) == 0 {
+		delete(hub.subscribers, gameID)
+	}
+}
+
+// publish sends e to everyone following the game. Subscribers who have fallen too far behind
+// miss the event rather than holding up the game
+func (hub *eventHub) publish(gameID string, e event) {
+	hub.Lock()
+	defer hub.Unlock()
+
+	for events := range hub.subscribers[gameID] {
+		select {
+		case events <- e:
+		default:
+		}
+	}
+}
+
+// timedOut is the event sent when a player runs out of time and is acted for
+type timedOut struct {
+	Seat int `json:"seat"`
+}
+
+// updateGame catches game up on everything which happens with time instead of requests, like
+// players running out of time to act, and lets everyone following it know. It needs to run on
+// the game's loop
+func (manager *GameManager) updateGame(gameID string, game *poker.Game) {
+	for {
+		seat, ok := game.EnforceShotClock()
+		if !ok {
+			break
+		}
+		manager.events.publish(gameID, event{Name: "timed-out", Data: timedOut{Seat: seat}})
+	}
+	manager.offerSeats(gameID, game)
+}
+
+// Events is a stream of server-sent events for a game. Since browsers can't send a body with
+// an EventSource, the game and passphrase are query parameters
+func (manager *GameManager) Events(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w, r, "GET")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendError(w, r, http.StatusInternalServerError, errors.New("Streaming is not supported"))
+		return
+	}
+
+	query := r.URL.Query()
+	gameID := query.Get("gameID")
+	loop, err := manager.findGame(gameID, query.Get("passphrase"))
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	events := manager.events.subscribe(gameID)
+	defer manager.events.unsubscribe(gameID, events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-loop.done:
+			return
+		case e := <-events:
+			data, err := json.Marshal(e.Data)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Name, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -8,113 +8,235 @@ import (
 	"github.com/brian-a-esch/httpoker/poker"
 )
 
-// GameManager is the top level object which exposes some http endpoints. There is a mutex
-// since there is shared state which needs to be managed. Reader writer locks were considered,
-// and if this were to be used for more than a few games, then it would probably be implemented.
-// But since typically we only play one game at a time, having one lock for all the games is fine
-// and it avoids having to lock at the game level
+// GameManager is the top level object which exposes some http endpoints. The registries of
+// games and tournaments have reader writer locks, which are only held long enough to find or
+// add one. Each game and each tournament runs on its own loop, so they don't wait on each other
 type GameManager struct {
-	sync.Mutex
-	gameCounter     int
-	games           map[int]poker.Game
-	gamePassphrases map[int]string
+	gamesLock sync.RWMutex
+	games     map[string]*gameLoop
+	events    *eventHub
+	// inviteKey signs invite links, so only someone with a game's passphrase can make them
+	inviteKey []byte
+	// clock is what games and tournaments tell time with, which tests replace
+	clock poker.Clock
+
+	tournamentsLock   sync.RWMutex
+	tournamentCounter int
+	tournaments       map[int]*tournamentLoop
 }
 
 // NewGameManager allocates a new GameManger
 func NewGameManager() GameManager {
-	return GameManager{gameCounter: 0, games: make(map[int]poker.Game), gamePassphrases: make(map[int]string)}
+	return GameManager{
+		games:       make(map[string]*gameLoop),
+		events:      newEventHub(),
+		inviteKey:   newInviteKey(),
+		clock:       poker.SystemClock,
+		tournaments: make(map[int]*tournamentLoop),
+	}
 }
 
-type createGameRequest struct {
-	Passphrase   string `json:"passphrase"`
-	StarterChips int    `json:"starterChips"`
-	BlindSize    int    `json:"blindSize"`
+// CreateGameRequest is the body for creating a game. Anything left out takes its default
+type CreateGameRequest struct {
+	Passphrase   string            `json:"passphrase"`
+	StarterChips int               `json:"starterChips"`
+	TableSize    int               `json:"tableSize"`
+	BlindSize    int               `json:"blindSize"`
+	Blinds       poker.Blinds      `json:"blinds"`
+	Jokers       int               `json:"jokers"`
+	WildValues   []poker.CardValue `json:"wildValues"`
+	// BettingStructure is used when there is no rotation of games
+	BettingStructure poker.BettingStructure `json:"bettingStructure"`
+	Limits           poker.BettingLimits    `json:"limits"`
+	Rotation         poker.Rotation         `json:"rotation"`
+	MaxRuns          int                    `json:"maxRuns"`
+	BombPots         poker.BombPots         `json:"bombPots"`
+	Rebuys           poker.RebuyRules       `json:"rebuys"`
+	ShotClock        poker.ShotClock        `json:"shotClock"`
+	Rake             poker.Rake             `json:"rake"`
+	Spectators       poker.SpectatorRules   `json:"spectators"`
+	// Private games aren't listed in the lobby, so only people who are told the id can find them
+	Private bool `json:"private"`
 }
 
-type getGameRequest struct {
-	GameID     int    `json:"gameID"`
+// GetGameRequest names a game, and has the passphrase to get into it
+type GetGameRequest struct {
+	GameID     string `json:"gameID"`
 	Passphrase string `json:"passphrase"`
 }
 
+// GetGameResponse is the state of a game everyone at the table can see
 // TODO should we just have game provide a "serialize" method?
-type getGameResponse struct {
-	GameID       int                  `json:"gameID"`
-	StarterChips int                  `json:"starterChips"`
-	BlindSize    int                  `json:"blindSize"`
-	EmptySeats   []int                `json:"emptySeats"`
-	Players      map[int]poker.Player `json:"players"`
+type GetGameResponse struct {
+	GameID             string               `json:"gameID"`
+	StarterChips       int                  `json:"starterChips"`
+	TableSize          int                  `json:"tableSize"`
+	BlindSize          int                  `json:"blindSize"`
+	Blinds             poker.Blinds         `json:"blinds"`
+	Straddler          *int                 `json:"straddler"`
+	Jokers             int                  `json:"jokers"`
+	WildValues         []poker.CardValue    `json:"wildValues"`
+	Limits             poker.BettingLimits  `json:"limits"`
+	CurrentGame        poker.GameType       `json:"currentGame"`
+	UpcomingGame       *poker.GameType      `json:"upcomingGame"`
+	HandsUntilNextGame int                  `json:"handsUntilNextGame"`
+	Chooser            *int                 `json:"chooser"`
+	MaxRuns            int                  `json:"maxRuns"`
+	BombPots           poker.BombPots       `json:"bombPots"`
+	BombPotVotes       []int                `json:"bombPotVotes"`
+	Rebuys             poker.RebuyRules     `json:"rebuys"`
+	ShotClock          poker.ShotClock      `json:"shotClock"`
+	Rake               poker.Rake           `json:"rake"`
+	RakeCollected      int                  `json:"rakeCollected"`
+	SpectatorRules     poker.SpectatorRules `json:"spectatorRules"`
+	// Spectators is how many people are watching without a seat
+	Spectators      int                       `json:"spectators"`
+	NextHandBombPot bool                      `json:"nextHandBombPot"`
+	EmptySeats      []int                     `json:"emptySeats"`
+	Reservations    map[int]poker.Reservation `json:"reservations"`
+	Waitlist        []string                  `json:"waitlist"`
+	Players         map[int]poker.Player      `json:"players"`
+	Hand            *poker.HandView           `json:"hand"`
+}
+
+// CreateGameResponse is the new game, along with the token only the host gets. The host token
+// changes settings which players shouldn't, like the spectator rules
+type CreateGameResponse struct {
+	GetGameResponse
+	HostToken string `json:"hostToken"`
+}
+
+func newGetGameResponse(gameID string, game *poker.Game) GetGameResponse {
+	response := GetGameResponse{
+		GameID:             gameID,
+		StarterChips:       game.StarterChips(),
+		TableSize:          game.TableSize(),
+		BlindSize:          game.BlindSize(),
+		Blinds:             game.Blinds(),
+		Jokers:             game.DeckOptions().Jokers,
+		WildValues:         game.DeckOptions().WildValues,
+		Limits:             game.Limits(),
+		CurrentGame:        game.CurrentGame(),
+		HandsUntilNextGame: game.HandsUntilNextGame(),
+		MaxRuns:            game.MaxRuns(),
+		BombPots:           game.BombPots(),
+		BombPotVotes:       game.BombPotVotes(),
+		Rebuys:             game.Rebuys(),
+		ShotClock:          game.ShotClock(),
+		Rake:               game.Rake(),
+		RakeCollected:      game.RakeCollected(),
+		SpectatorRules:     game.SpectatorRules(),
+		Spectators:         game.Spectators(),
+		NextHandBombPot:    game.NextHandBombPot(),
+		EmptySeats:         game.EmptySeats(),
+		Reservations:       game.Reservations(),
+		Waitlist:           game.Waitlist(),
+		Players:            game.Players(),
+	}
+
+	// These are left as null when there is nothing to announce
+	if upcoming, ok := game.UpcomingGame(); ok {
+		response.UpcomingGame = &upcoming
+	}
+	if chooser, ok := game.Chooser(); ok {
+		response.Chooser = &chooser
+	}
+	if straddler, ok := game.Straddler(); ok {
+		response.Straddler = &straddler
+	}
+	if hand, ok := game.HandView(-1); ok {
+		response.Hand = &hand
+	}
+
+	return response
 }
 
 // Game is a restful endpoint for getting a poker game
 func (manager *GameManager) Game(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
-		http.Error(w, "Must be POST", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r, "POST")
+		return
 	}
 
-	get := getGameRequest{}
+	get := GetGameRequest{}
 	if ok := decodeJSONBody(w, r, &get); !ok {
 		return
 	}
 
-	manager.Lock()
-	defer manager.Unlock()
-
-	game, err := manager.resolveGame(get.GameID, get.Passphrase)
+	loop, err := manager.findGame(get.GameID, get.Passphrase)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		sendError(w, r, http.StatusBadRequest, err)
+		return
 	}
+	loop.do(w, r, func(game *poker.Game) {
+		manager.updateGame(get.GameID, game)
 
-	response := getGameResponse{
-		GameID:       get.GameID,
-		StarterChips: game.StarterChips(),
-		BlindSize:    game.BlindSize(),
-		EmptySeats:   game.EmptySeats(),
-		Players:      game.Players(),
+		sendJSONResponse(w, r, newGetGameResponse(get.GameID, game))
+	})
+}
+
+// options are the rules to create the game with, timed by clock
+func (create *CreateGameRequest) options(clock poker.Clock) poker.GameOptions {
+	return poker.GameOptions{
+		StarterChips: create.StarterChips,
+		TableSize:    create.TableSize,
+		BlindSize:    create.BlindSize,
+		Blinds:       create.Blinds,
+		Deck:         poker.DeckOptions{Jokers: create.Jokers, WildValues: create.WildValues},
+		Betting:      create.BettingStructure,
+		Limits:       create.Limits,
+		Rotation:     create.Rotation,
+		MaxRuns:      create.MaxRuns,
+		BombPots:     create.BombPots,
+		Rebuys:       create.Rebuys,
+		ShotClock:    create.ShotClock,
+		Rake:         create.Rake,
+		Spectators:   create.Spectators,
+		Clock:        clock,
 	}
-	sendJSONResponse(w, response)
 }
 
 // CreateGame creates a game in the GameManager
 func (manager *GameManager) CreateGame(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
-		http.Error(w, "Must be POST", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r, "POST")
+		return
 	}
 
-	create := createGameRequest{}
+	create := CreateGameRequest{}
 	if ok := decodeJSONBody(w, r, &create); !ok {
 		return
 	}
 
-	game, err := poker.NewGame(create.StarterChips, create.BlindSize)
+	game, err := poker.NewGameWithOptions(create.options(manager.clock))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		sendError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	manager.Lock()
-	defer manager.Unlock()
-
-	gameID := manager.gameCounter
-	manager.gameCounter++
-	if _, ok := manager.games[gameID]; ok {
-		w.WriteHeader(http.StatusInternalServerError)
+	loop, err := manager.addGame(&game, create.Passphrase, create.Private)
+	if err != nil {
+		sendError(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	manager.games[gameID] = game
-	manager.gamePassphrases[gameID] = create.Passphrase
 
-	response := getGameResponse{GameID: gameID, StarterChips: game.StarterChips(), BlindSize: game.BlindSize()}
-	sendJSONResponse(w, response)
+	loop.do(w, r, func(game *poker.Game) {
+		sendJSONResponse(w, r, CreateGameResponse{GetGameResponse: newGetGameResponse(loop.gameID, game), HostToken: loop.hostToken})
+	})
 }
 
-type addPlayerRequest struct {
+// AddPlayerRequest sits a player down at a game. Claim is the claim from the reservation, for
+// sitting in a reserved seat. For a seat offered from the waitlist, it is the waitlist secret
+type AddPlayerRequest struct {
 	Name       string `json:"name"`
 	Passphrase string `json:"passphrase"`
 	Seat       int    `json:"seat"`
-	GameID     int    `json:"gameID"`
+	GameID     string `json:"gameID"`
+	Claim      int    `json:"claim"`
 }
 
-type addPlayerResponse struct {
+// AddPlayerResponse has the secret the player needs for everything they do from then on
+type AddPlayerResponse struct {
 	Player poker.Player `json:"player"`
 	Secret int          `json:"secret"`
 }
@@ -122,49 +244,360 @@ type addPlayerResponse struct {
 // AddPlayer adds a player to the game
 func (manager *GameManager) AddPlayer(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
-		http.Error(w, "Must be POST", http.StatusMethodNotAllowed)
+		methodNotAllowed(w, r, "POST")
+		return
 	}
 
-	addRequest := addPlayerRequest{}
+	addRequest := AddPlayerRequest{}
 	if ok := decodeJSONBody(w, r, &addRequest); !ok {
 		return
 	}
 
-	manager.Lock()
-	defer manager.Unlock()
+	loop, err := manager.findGame(addRequest.GameID, addRequest.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		player, err := game.ClaimSeat(addRequest.Name, addRequest.Seat, addRequest.Claim)
+		if err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		sendJSONResponse(w, r, AddPlayerResponse{
+			Player: player,
+			Secret: player.Secret(),
+		})
+	})
+}
+
+type chooseGameRequest struct {
+	GameID     string         `json:"gameID"`
+	Passphrase string         `json:"passphrase"`
+	Seat       int            `json:"seat"`
+	Secret     int            `json:"secret"`
+	Game       poker.GameType `json:"game"`
+}
+
+// ChooseGame lets the dealer pick the next game when playing dealer's choice
+func (manager *GameManager) ChooseGame(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	choose := chooseGameRequest{}
+	if ok := decodeJSONBody(w, r, &choose); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(choose.GameID, choose.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, choose.Seat, choose.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		if err := game.ChooseGame(choose.Seat, choose.Game); err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		sendJSONResponse(w, r, newGetGameResponse(choose.GameID, game))
+	})
+}
+
+// PlayerRequest is the body of requests made by a seated player
+type PlayerRequest struct {
+	GameID     string `json:"gameID"`
+	Passphrase string `json:"passphrase"`
+	Seat       int    `json:"seat"`
+	Secret     int    `json:"secret"`
+}
+
+// StartHand deals the next hand of the game. Any seated player can start it
+func (manager *GameManager) StartHand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	start := PlayerRequest{}
+	if ok := decodeJSONBody(w, r, &start); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(start.GameID, start.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, start.Seat, start.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		if err := game.StartHand(); err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		hand, _ := game.HandView(start.Seat)
+		sendJSONResponse(w, r, hand)
+	})
+}
+
+// Hand gets the current hand as seen by a player, including their hole cards
+func (manager *GameManager) Hand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	get := PlayerRequest{}
+	if ok := decodeJSONBody(w, r, &get); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(get.GameID, get.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, get.Seat, get.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		hand, ok := game.HandView(get.Seat)
+		if !ok {
+			sendError(w, r, http.StatusNotFound, errors.New("No hand has been dealt"))
+			return
+		}
+		sendJSONResponse(w, r, hand)
+	})
+}
+
+type straddleRequest struct {
+	GameID     string `json:"gameID"`
+	Passphrase string `json:"passphrase"`
+	Seat       int    `json:"seat"`
+	Secret     int    `json:"secret"`
+	Cancel     bool   `json:"cancel"`
+}
+
+// Straddle lets a player straddle the next hand, or take back their straddle
+func (manager *GameManager) Straddle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	straddle := straddleRequest{}
+	if ok := decodeJSONBody(w, r, &straddle); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(straddle.GameID, straddle.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, straddle.Seat, straddle.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		if straddle.Cancel {
+			err = game.CancelStraddle(straddle.Seat)
+		} else {
+			err = game.Straddle(straddle.Seat)
+		}
+		if err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		sendJSONResponse(w, r, newGetGameResponse(straddle.GameID, game))
+	})
+}
+
+type runItRequest struct {
+	GameID     string `json:"gameID"`
+	Passphrase string `json:"passphrase"`
+	Seat       int    `json:"seat"`
+	Secret     int    `json:"secret"`
+	Runs       int    `json:"runs"`
+}
+
+// RunIt is a vote by a player all in for how many times to run the rest of the board
+func (manager *GameManager) RunIt(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	runIt := runItRequest{}
+	if ok := decodeJSONBody(w, r, &runIt); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(runIt.GameID, runIt.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, runIt.Seat, runIt.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		if err := game.ChooseRuns(runIt.Seat, runIt.Runs); err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		hand, _ := game.HandView(runIt.Seat)
+		sendJSONResponse(w, r, hand)
+	})
+}
+
+type bombPotRequest struct {
+	GameID     string `json:"gameID"`
+	Passphrase string `json:"passphrase"`
+	Seat       int    `json:"seat"`
+	Secret     int    `json:"secret"`
+	Cancel     bool   `json:"cancel"`
+}
+
+// BombPot lets a player vote for the next hand to be a bomb pot, or take back their vote
+func (manager *GameManager) BombPot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	bombPot := bombPotRequest{}
+	if ok := decodeJSONBody(w, r, &bombPot); !ok {
+		return
+	}
 
-	game, err := manager.resolveGame(addRequest.GameID, addRequest.Passphrase)
+	loop, err := manager.findGame(bombPot.GameID, bombPot.Passphrase)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		sendError(w, r, http.StatusBadRequest, err)
+		return
 	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, bombPot.Seat, bombPot.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		if bombPot.Cancel {
+			err = game.CancelBombPotVote(bombPot.Seat)
+		} else {
+			err = game.VoteBombPot(bombPot.Seat)
+		}
+		if err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
 
-	player, err := game.AddPlayer(addRequest.Name, addRequest.Seat)
+		sendJSONResponse(w, r, newGetGameResponse(bombPot.GameID, game))
+	})
+}
+
+// Rebuy buys a player more chips between hands
+func (manager *GameManager) Rebuy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	rebuy := PlayerRequest{}
+	if ok := decodeJSONBody(w, r, &rebuy); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(rebuy.GameID, rebuy.Passphrase)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		sendError(w, r, http.StatusBadRequest, err)
+		return
 	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, rebuy.Seat, rebuy.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		if err := game.Rebuy(rebuy.Seat); err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
 
-	sendJSONResponse(w, addPlayerResponse{
-		Player: player,
-		Secret: player.Secret(),
+		sendJSONResponse(w, r, newGetGameResponse(rebuy.GameID, game))
 	})
+}
 
+// ActRequest takes an action for a seated player
+type ActRequest struct {
+	GameID     string       `json:"gameID"`
+	Passphrase string       `json:"passphrase"`
+	Seat       int          `json:"seat"`
+	Secret     int          `json:"secret"`
+	Action     poker.Action `json:"action"`
 }
 
-func (manager *GameManager) resolveGame(gameID int, passphrase string) (*poker.Game, error) {
-	// The error functions here are intentionally obtuse
-	game, ok := manager.games[gameID]
-	if !ok {
-		return nil, errors.New("Could not find/load game")
+// Act takes an action for the player whose turn it is
+func (manager *GameManager) Act(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
 	}
 
-	foundPassphrase, ok := manager.gamePassphrases[gameID]
-	if !ok {
-		return nil, errors.New("Could not find/load game")
+	act := ActRequest{}
+	if ok := decodeJSONBody(w, r, &act); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(act.GameID, act.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
 	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, act.Seat, act.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		// A player who ran out of time has already been acted for
+		manager.updateGame(act.GameID, game)
+		if err := game.Act(act.Seat, act.Action); err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		hand, _ := game.HandView(act.Seat)
+		sendJSONResponse(w, r, hand)
+	})
+}
 
-	if passphrase != foundPassphrase {
-		return nil, errors.New("Could not find/load game")
+func resolvePlayer(game *poker.Game, seat int, secret int) error {
+	// Which seats are taken is public, but a wrong secret looks the same as an empty seat
+	player, ok := game.Players()[seat]
+	if !ok || player.Secret() != secret {
+		return newRequestError(CodePlayerNotFound, "secret", "Could not find player")
 	}
 
-	return &game, nil
+	return nil
 }
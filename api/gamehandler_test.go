@@ -6,7 +6,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
 )
 
 func createTestRequest(method string, val interface{}) *http.Request {
@@ -34,7 +38,7 @@ func readResponse(r *http.Response, dst interface{}) {
 }
 
 func TestAddPlayerApi(t *testing.T) {
-	gameReq := createGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
 	req := createTestRequest("POST", gameReq)
 
 	recorder := httptest.NewRecorder()
@@ -45,10 +49,10 @@ func TestAddPlayerApi(t *testing.T) {
 		t.Error()
 	}
 
-	gameResponse := getGameResponse{}
+	gameResponse := GetGameResponse{}
 	readResponse(recorder.Result(), &gameResponse)
 
-	playerReq := addPlayerRequest{Name: "foo", Seat: 3, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+	playerReq := AddPlayerRequest{Name: "foo", Seat: 3, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
 	req = createTestRequest("POST", playerReq)
 	recorder = httptest.NewRecorder()
 	handler = http.HandlerFunc(gameManager.AddPlayer)
@@ -57,7 +61,7 @@ func TestAddPlayerApi(t *testing.T) {
 		t.Error()
 	}
 
-	playerResponse := addPlayerResponse{}
+	playerResponse := AddPlayerResponse{}
 	readResponse(recorder.Result(), &playerResponse)
 
 	if playerResponse.Player.Chips != gameReq.StarterChips {
@@ -67,3 +71,282 @@ func TestAddPlayerApi(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestChooseGameApi(t *testing.T) {
+	gameManager := NewGameManager()
+	rotation := poker.Rotation{
+		Games:         []poker.GameType{{Variant: poker.HoldEm}, {Variant: poker.Omaha, Betting: poker.PotLimit}},
+		DealersChoice: true,
+	}
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, Rotation: rotation}
+	recorder := httptest.NewRecorder()
+	http.HandlerFunc(gameManager.CreateGame).ServeHTTP(recorder, createTestRequest("POST", gameReq))
+
+	gameResponse := GetGameResponse{}
+	readResponse(recorder.Result(), &gameResponse)
+	if gameResponse.UpcomingGame != nil || gameResponse.Chooser != nil {
+		t.Error("No one is seated to choose the game yet")
+	}
+
+	secrets := make(map[int]int)
+	for _, seat := range []int{1, 5} {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		recorder = httptest.NewRecorder()
+		http.HandlerFunc(gameManager.AddPlayer).ServeHTTP(recorder, createTestRequest("POST", playerReq))
+
+		playerResponse := AddPlayerResponse{}
+		readResponse(recorder.Result(), &playerResponse)
+		secrets[seat] = playerResponse.Secret
+	}
+
+	chooseReq := chooseGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 5, Secret: secrets[5], Game: rotation.Games[1]}
+	recorder = httptest.NewRecorder()
+	http.HandlerFunc(gameManager.ChooseGame).ServeHTTP(recorder, createTestRequest("POST", chooseReq))
+	if recorder.Code != http.StatusBadRequest {
+		t.Error("Only the dealer can choose the game")
+	}
+
+	chooseReq.Seat = 1
+	chooseReq.Secret = secrets[1]
+	recorder = httptest.NewRecorder()
+	http.HandlerFunc(gameManager.ChooseGame).ServeHTTP(recorder, createTestRequest("POST", chooseReq))
+	if recorder.Code != http.StatusOK {
+		t.Error(recorder.Body.String())
+	}
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	recorder = httptest.NewRecorder()
+	http.HandlerFunc(gameManager.Game).ServeHTTP(recorder, createTestRequest("POST", getReq))
+	readResponse(recorder.Result(), &gameResponse)
+	if gameResponse.UpcomingGame == nil || *gameResponse.UpcomingGame != rotation.Games[1] {
+		t.Error("Status should announce the chosen game")
+	}
+}
+
+func serveTestRequest(handler http.HandlerFunc, val interface{}) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, createTestRequest("POST", val))
+	return recorder
+}
+
+func TestPlayHandApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, BettingStructure: poker.PotLimit}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+	if gameResponse.CurrentGame.Betting != poker.PotLimit {
+		t.Error()
+	}
+
+	players := make(map[int]AddPlayerResponse)
+	for _, seat := range []int{0, 1} {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		players[seat] = playerResponse
+	}
+
+	startReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	recorder := serveTestRequest(gameManager.StartHand, startReq)
+	if recorder.Code != http.StatusOK {
+		t.Fatal(recorder.Body.String())
+	}
+
+	hand := poker.HandView{}
+	readResponse(recorder.Result(), &hand)
+	if len(hand.Seats[0].HoleCards) != 2 || len(hand.Seats[1].HoleCards) != 0 {
+		t.Error("Players should only see their own cards")
+	}
+	if hand.ToAct != 0 || hand.Options == nil || hand.Options.MaxRaiseTo != 30 {
+		t.Error("Heads up the button acts first, and can raise the pot")
+	}
+
+	actReq := ActRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	actReq.Action = poker.Action{Type: poker.Raise, Amount: 40}
+	if recorder = serveTestRequest(gameManager.Act, actReq); recorder.Code != http.StatusBadRequest {
+		t.Error("Cannot raise more than the pot")
+	}
+
+	actReq.Action = poker.Action{Type: poker.Fold}
+	if recorder = serveTestRequest(gameManager.Act, actReq); recorder.Code != http.StatusOK {
+		t.Error(recorder.Body.String())
+	}
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	readResponse(serveTestRequest(gameManager.Game, getReq).Result(), &gameResponse)
+	if gameResponse.Hand == nil || !gameResponse.Hand.Over || gameResponse.Players[1].Chips != 105 {
+		t.Error("Folding should end the hand and award the blinds")
+	}
+}
+
+func TestBombPotApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, BombPots: poker.BombPots{Ante: 10}}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	players := make(map[int]AddPlayerResponse)
+	for _, seat := range []int{0, 1} {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		players[seat] = playerResponse
+	}
+
+	for _, seat := range []int{0, 1} {
+		voteReq := bombPotRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: seat, Secret: players[seat].Secret}
+		recorder := serveTestRequest(gameManager.BombPot, voteReq)
+		if recorder.Code != http.StatusOK {
+			t.Fatal(recorder.Body.String())
+		}
+		readResponse(recorder.Result(), &gameResponse)
+	}
+	if !gameResponse.NextHandBombPot || len(gameResponse.BombPotVotes) != 2 {
+		t.Error("Everyone voted for a bomb pot")
+	}
+
+	startReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	hand := poker.HandView{}
+	readResponse(serveTestRequest(gameManager.StartHand, startReq).Result(), &hand)
+	if !hand.BombPot || hand.Pot != 20 || len(hand.Board) != 3 {
+		t.Error("Bomb pot should start on the flop")
+	}
+
+	runItReq := runItRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret, Runs: 2}
+	if recorder := serveTestRequest(gameManager.RunIt, runItReq); recorder.Code != http.StatusBadRequest {
+		t.Error("No one is all in")
+	}
+}
+
+func TestRebuyApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, Rebuys: poker.RebuyRules{MaxRebuys: poker.Unlimited, Threshold: 100}}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	playerReq := AddPlayerRequest{Name: "foo", Seat: 3, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+	playerResponse := AddPlayerResponse{}
+	readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+
+	rebuyReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 3, Secret: playerResponse.Secret}
+	recorder := serveTestRequest(gameManager.Rebuy, rebuyReq)
+	if recorder.Code != http.StatusOK {
+		t.Fatal(recorder.Body.String())
+	}
+	readResponse(recorder.Result(), &gameResponse)
+	if player := gameResponse.Players[3]; player.Chips != 200 || player.BuyIns.Total != 200 {
+		t.Error(player)
+	}
+}
+
+// testClock is a clock which only moves when the test says. Games read it on their own
+// goroutines, so it has a lock
+type testClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2020, time.January, 1, 19, 0, 0, 0, time.UTC)}
+}
+
+func (clock *testClock) Now() time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	return clock.now
+}
+
+func (clock *testClock) advance(duration time.Duration) {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	clock.now = clock.now.Add(duration)
+}
+
+func TestShotClockApi(t *testing.T) {
+	gameManager := NewGameManager()
+	clock := newTestClock()
+	gameManager.clock = clock
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, ShotClock: poker.ShotClock{Action: 30 * time.Second}}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	players := make(map[int]AddPlayerResponse)
+	for _, seat := range []int{0, 1} {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		players[seat] = playerResponse
+	}
+
+	startReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	serveTestRequest(gameManager.StartHand, startReq)
+	clock.advance(30 * time.Second)
+
+	// The button runs out of time facing the big blind, so they fold
+	actReq := ActRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	actReq.Action = poker.Action{Type: poker.Call}
+	if recorder := serveTestRequest(gameManager.Act, actReq); recorder.Code != http.StatusBadRequest {
+		t.Error("Seat 0 already ran out of time")
+	}
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	readResponse(serveTestRequest(gameManager.Game, getReq).Result(), &gameResponse)
+	if gameResponse.Hand == nil || !gameResponse.Hand.Over || gameResponse.Players[1].Chips != 105 {
+		t.Error(gameResponse.Hand)
+	}
+}
+
+func TestGameStatePersists(t *testing.T) {
+	gameManager := NewGameManager()
+	blinds := poker.Blinds{SmallBlind: 5, BigBlind: 10, Straddles: poker.Straddles{UnderTheGun: true}}
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, Blinds: blinds}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	secrets := make([]int, 0)
+	for seat := 0; seat < 3; seat++ {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		secrets = append(secrets, playerResponse.Secret)
+	}
+
+	// Someone can straddle the first hand, which only the game's scalar fields remember
+	straddler := -1
+	for seat := 0; seat < 3 && straddler == -1; seat++ {
+		straddleReq := straddleRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: seat, Secret: secrets[seat]}
+		if serveTestRequest(gameManager.Straddle, straddleReq).Code == http.StatusOK {
+			straddler = seat
+		}
+	}
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	readResponse(serveTestRequest(gameManager.Game, getReq).Result(), &gameResponse)
+	if straddler == -1 || gameResponse.Straddler == nil || *gameResponse.Straddler != straddler {
+		t.Fatal("The straddle should still be there on the next request")
+	}
+
+	buttons := make(map[int]bool)
+	for number := 1; number <= 3; number++ {
+		handReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: secrets[0]}
+		hand := poker.HandView{}
+		readResponse(serveTestRequest(gameManager.StartHand, handReq).Result(), &hand)
+		for !hand.Over {
+			actReq := ActRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: hand.ToAct, Secret: secrets[hand.ToAct],
+				Action: poker.Action{Type: poker.Fold}}
+			readResponse(serveTestRequest(gameManager.Act, actReq).Result(), &hand)
+		}
+
+		statusResponse := GetGameResponse{}
+		readResponse(serveTestRequest(gameManager.Game, getReq).Result(), &statusResponse)
+		if statusResponse.Hand == nil || statusResponse.Hand.Number != number || statusResponse.Hand.Button != hand.Button {
+			t.Fatal("Hand", number, "should be the last hand on the next request")
+		}
+		if statusResponse.Straddler != nil {
+			t.Error("The straddle was used up by the first hand")
+		}
+		buttons[hand.Button] = true
+	}
+	if len(buttons) != 3 {
+		t.Error("The button should move every hand", buttons)
+	}
+}
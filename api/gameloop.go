@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// gameTickInterval is how often a game's loop checks on things which happen with time instead
+// of requests, like players running out of time to act and seat offers running out
+const gameTickInterval = time.Second
+
+// command is something a request does to a game, which is run on the game's loop
+type command func(game *poker.Game)
+
+// errGameClosed is why a command didn't run on a game's loop when the game has been closed
+var errGameClosed = errors.New("Game has been closed")
+
+// gameLoop runs a game on its own goroutine. Requests send it commands, which it runs one at a
+// time, so nothing else ever touches the game and it doesn't need a lock. Between commands the
+// loop ticks to keep the game up to date with the clock, and it runs until the game is closed.
+// A panic while running the game closes it, since the game could have been left half changed
+type gameLoop struct {
+	gameID string
+	// passphrase, hostToken, private and created never change, so they can be checked without
+	// going through the loop
+	passphrase string
+	hostToken  string
+	private    bool
+	created    time.Time
+	commands   chan func(game *poker.Game) error
+	stop       chan struct{}
+	stopOnce   sync.Once
+	done       chan struct{}
+}
+
+func (manager *GameManager) startGameLoop(gameID string, game *poker.Game, passphrase string, hostToken string, private bool) *gameLoop {
+	loop := &gameLoop{
+		gameID:     gameID,
+		passphrase: passphrase,
+		hostToken:  hostToken,
+		private:    private,
+		created:    time.Now(),
+		commands:   make(chan func(game *poker.Game) error),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go manager.runGameLoop(loop, game)
+	return loop
+}
+
+func (manager *GameManager) runGameLoop(loop *gameLoop, game *poker.Game) {
+	defer close(loop.done)
+
+	ticker := time.NewTicker(gameTickInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case run := <-loop.commands:
+			err = run(game)
+		case <-ticker.C:
+			err = loop.safely(func() { manager.updateGame(loop.gameID, game) })
+		case <-loop.stop:
+			return
+		}
+
+		if err != nil {
+			manager.removeGame(loop)
+			return
+		}
+	}
+}
+
+// safely runs step, turning a panic into an error so one broken game can't take down the server
+func (loop *gameLoop) safely(step func()) (err error) {
+	defer func() {
+		if panicked := recover(); panicked != nil {
+			log.Printf("Game %s panicked: %v\n%s", loop.gameID, panicked, debug.Stack())
+			err = fmt.Errorf("Game %s ran into a problem and has been closed", loop.gameID)
+		}
+	}()
+	step()
+	return nil
+}
+
+// run has the loop run a command, and waits for it to finish. Returns errGameClosed if the
+// game has been closed and the command never ran, or an error if the command panicked
+func (loop *gameLoop) run(run command) error {
+	finished := make(chan error, 1)
+	select {
+	case loop.commands <- func(game *poker.Game) error {
+		err := loop.safely(func() { run(game) })
+		finished <- err
+		return err
+	}:
+	case <-loop.done:
+		return errGameClosed
+	}
+
+	return <-finished
+}
+
+// do runs a request's command on the loop. The command responds to the request, unless the
+// game has been closed or the command panicked, which are responded to here
+func (loop *gameLoop) do(w http.ResponseWriter, r *http.Request, run command) bool {
+	err := loop.run(run)
+	if err == errGameClosed {
+		sendError(w, r, http.StatusGone, newRequestError(CodeGameClosed, "gameID", "Game %s has been closed", loop.gameID))
+	} else if err != nil {
+		sendError(w, r, http.StatusInternalServerError, err)
+	}
+	return err == nil
+}
+
+// close stops the loop, and waits for the command it is running to finish
+func (loop *gameLoop) close() {
+	loop.stopOnce.Do(func() { close(loop.stop) })
+	<-loop.done
+}
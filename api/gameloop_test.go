@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func TestGameLoopTicks(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, ShotClock: poker.ShotClock{Action: time.Nanosecond}}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	secrets := make([]int, 0)
+	for seat := 0; seat < 2; seat++ {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		secrets = append(secrets, playerResponse.Secret)
+	}
+
+	events := gameManager.events.subscribe(gameResponse.GameID)
+	defer gameManager.events.unsubscribe(gameResponse.GameID, events)
+
+	handReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: secrets[0]}
+	hand := poker.HandView{}
+	readResponse(serveTestRequest(gameManager.StartHand, handReq).Result(), &hand)
+
+	// Nobody makes a request, so it is the loop's tick which catches the player running out of time
+	select {
+	case e := <-events:
+		if e.Name != "timed-out" || e.Data.(timedOut).Seat != hand.ToAct {
+			t.Error(e)
+		}
+	case <-time.After(5 * gameTickInterval):
+		t.Fatal("The loop never ticked")
+	}
+}
+
+func TestCloseGame(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	loop, err := gameManager.findGame(gameResponse.GameID, gameReq.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gameManager.CloseGame(gameResponse.GameID) || gameManager.CloseGame(gameResponse.GameID) {
+		t.Error("The game can only be closed once")
+	}
+
+	// A request which found the game before it closed is told it is gone
+	recorder := httptest.NewRecorder()
+	if loop.do(recorder, createTestRequest("POST", nil), func(game *poker.Game) {}) || recorder.Code != http.StatusGone {
+		t.Error(recorder.Code)
+	}
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	if recorder := serveTestRequest(gameManager.Game, getReq); recorder.Code != http.StatusBadRequest {
+		t.Error(recorder.Code)
+	}
+
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+	gameManager.Close()
+	if len(gameManager.games) != 0 {
+		t.Error("Every game should be closed")
+	}
+}
+
+func TestGameLoopPanics(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+	other := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &other)
+
+	loop, err := gameManager.findGame(gameResponse.GameID, gameReq.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	if loop.do(recorder, createTestRequest("POST", nil), func(game *poker.Game) { panic("broken") }) {
+		t.Error("The command never finished")
+	}
+	response := ErrorResponse{}
+	readResponse(recorder.Result(), &response)
+	if recorder.Code != http.StatusInternalServerError || response.Code != CodeInternal {
+		t.Error(recorder.Code, response)
+	}
+
+	// The game is closed, since it could have been left half changed, but the others keep going
+	<-loop.done
+	if _, err := gameManager.findGame(gameResponse.GameID, gameReq.Passphrase); err == nil {
+		t.Error("The game should be gone")
+	}
+	getReq := GetGameRequest{GameID: other.GameID, Passphrase: gameReq.Passphrase}
+	if recorder := serveTestRequest(gameManager.Game, getReq); recorder.Code != http.StatusOK {
+		t.Error(recorder.Code)
+	}
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// DefaultInviteMinutes is how long an invite lasts when the host doesn't say
+const DefaultInviteMinutes = 24 * 60
+
+// invitePagePath is the page of the web app which opens invite links
+const invitePagePath = "/game"
+
+// InviteRequest makes an invite link for a game. With a seat, the seat is held for name until
+// the invite expires
+type InviteRequest struct {
+	GameID     string `json:"gameID"`
+	Passphrase string `json:"passphrase"`
+	Seat       *int   `json:"seat"`
+	Name       string `json:"name"`
+	// Minutes is how long the invite lasts, which is DefaultInviteMinutes when left out
+	Minutes int `json:"minutes"`
+	// Spectate makes an invite to watch the game with Spectate. It can't hold a seat or stand in
+	// for the passphrase, so it never shows more than spectators see
+	Spectate bool `json:"spectate"`
+}
+
+// InviteResponse is the invite, and the link to send to whoever is invited
+type InviteResponse struct {
+	Invite  string    `json:"invite"`
+	URL     string    `json:"url"`
+	Expires time.Time `json:"expires"`
+}
+
+// JoinRequest redeems an invite
+type JoinRequest struct {
+	Invite string `json:"invite"`
+}
+
+// JoinResponse lets someone with an invite into the game. The invite goes wherever the game's
+// passphrase would, until it expires. When a seat was held for them, they sit in it with
+// AddPlayer using the seat and claim
+type JoinResponse struct {
+	Game  GetGameResponse `json:"game"`
+	Seat  *int            `json:"seat"`
+	Name  string          `json:"name"`
+	Claim int             `json:"claim,omitempty"`
+}
+
+// invite is what an invite link holds. It is signed, so it can't be changed or made up without
+// the manager's key
+type invite struct {
+	GameID   string `json:"g"`
+	Seat     *int   `json:"s,omitempty"`
+	Name     string `json:"n,omitempty"`
+	Claim    int    `json:"c,omitempty"`
+	Expires  int64  `json:"e"`
+	Spectate bool   `json:"w,omitempty"`
+}
+
+// newInviteKey makes a random key for signing invites. Games don't outlive the server, so
+// neither does the key
+func newInviteKey() []byte {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// signInvite encodes an invite and its signature into a token which can go in a url
+func (manager *GameManager) signInvite(inv invite) (string, error) {
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, manager.inviteKey)
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// readInvite checks an invite token was signed by the manager and hasn't expired
+func (manager *GameManager) readInvite(token string) (invite, error) {
+	inv := invite{}
+	invalid := newRequestError(CodeInvalidInvite, "invite", "Invite is not valid")
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return inv, invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return inv, invalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return inv, invalid
+	}
+
+	mac := hmac.New(sha256.New, manager.inviteKey)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return inv, invalid
+	}
+	if err := json.Unmarshal(payload, &inv); err != nil {
+		return inv, invalid
+	}
+	if !manager.clock.Now().Before(time.Unix(inv.Expires, 0)) {
+		return inv, newRequestError(CodeInvalidInvite, "invite", "Invite has expired")
+	}
+	return inv, nil
+}
+
+// invitedTo tells if token is an invite to sit at the game with gameID which is still good
+func (manager *GameManager) invitedTo(gameID string, token string) bool {
+	inv, err := manager.readInvite(token)
+	return err == nil && inv.GameID == gameID && !inv.Spectate
+}
+
+// inviteURL is the link to the invite on the server the request came to
+func inviteURL(r *http.Request, token string) string {
+	link := url.URL{Scheme: "http", Host: r.Host, Path: invitePagePath, RawQuery: url.Values{"invite": {token}}.Encode()}
+	if r.TLS != nil {
+		link.Scheme = "https"
+	}
+	return link.String()
+}
+
+// Invite makes a signed link to a game, so the host can send it instead of the game's id and
+// passphrase. It can hold a seat for the player being invited
+func (manager *GameManager) Invite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	inviteReq := InviteRequest{}
+	if ok := decodeJSONBody(w, r, &inviteReq); !ok {
+		return
+	}
+	if inviteReq.Minutes < 0 {
+		sendError(w, r, http.StatusBadRequest, newRequestError(CodeInvalidField, "minutes", "Minutes must not be negative"))
+		return
+	}
+	if inviteReq.Minutes == 0 {
+		inviteReq.Minutes = DefaultInviteMinutes
+	}
+	if inviteReq.Spectate && inviteReq.Seat != nil {
+		sendError(w, r, http.StatusBadRequest, newRequestError(CodeInvalidField, "seat", "Invites to spectate can't hold a seat"))
+		return
+	}
+
+	// Only the passphrase makes invites, so one invite can't be used to hand out more
+	loop, err := manager.findGame(inviteReq.GameID, inviteReq.Passphrase)
+	if err == nil && inviteReq.Passphrase != loop.passphrase {
+		err = newRequestError(CodeWrongPassphrase, "passphrase", "Only the game's passphrase can make invites")
+	}
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		duration := time.Duration(inviteReq.Minutes) * time.Minute
+		inv := invite{GameID: loop.gameID, Seat: inviteReq.Seat, Name: inviteReq.Name, Spectate: inviteReq.Spectate}
+		expires := manager.clock.Now().Add(duration)
+		if inviteReq.Seat != nil {
+			reservation, err := game.ReserveSeat(*inviteReq.Seat, inviteReq.Name, duration)
+			if err != nil {
+				sendError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			// The claim is only in the signed invite, so only whoever has it can take the seat
+			inv.Claim = reservation.Claim()
+			expires = reservation.Expires
+		}
+		inv.Expires = expires.Unix()
+
+		token, err := manager.signInvite(inv)
+		if err != nil {
+			sendError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		sendJSONResponse(w, r, InviteResponse{Invite: token, URL: inviteURL(r, token), Expires: expires})
+	})
+}
+
+// Join lets someone into a game with an invite, without giving away the game's passphrase
+func (manager *GameManager) Join(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	join := JoinRequest{}
+	if ok := decodeJSONBody(w, r, &join); !ok {
+		return
+	}
+
+	inv, err := manager.readInvite(join.Invite)
+	if err == nil && inv.Spectate {
+		err = newRequestError(CodeInvalidInvite, "invite", "Invite is to spectate the game")
+	}
+	if err != nil {
+		sendError(w, r, http.StatusForbidden, err)
+		return
+	}
+
+	loop, err := manager.lookupGame(inv.GameID)
+	if err != nil {
+		sendError(w, r, http.StatusNotFound, newRequestError(CodeGameNotFound, "invite", "Game %s is over", inv.GameID))
+		return
+	}
+
+	loop.do(w, r, func(game *poker.Game) {
+		sendJSONResponse(w, r, JoinResponse{
+			Game:  newGetGameResponse(loop.gameID, game),
+			Seat:  inv.Seat,
+			Name:  inv.Name,
+			Claim: inv.Claim,
+		})
+	})
+}
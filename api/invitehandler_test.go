@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInviteApi(t *testing.T) {
+	gameManager := NewGameManager()
+	clock := newTestClock()
+	gameManager.clock = clock
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, TableSize: 6}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	seat := 2
+	inviteReq := InviteRequest{GameID: gameResponse.GameID, Passphrase: "foobar", Seat: &seat, Name: "alice", Minutes: 30}
+	recorder := serveTestRequest(gameManager.Invite, inviteReq)
+	checkResponse(t, "invite", recorder)
+	inviteResponse := InviteResponse{}
+	readResponse(recorder.Result(), &inviteResponse)
+	link, err := url.Parse(inviteResponse.URL)
+	if err != nil || link.Path != "/game" || link.Query().Get("invite") != inviteResponse.Invite {
+		t.Fatal(inviteResponse.URL)
+	}
+	if !inviteResponse.Expires.Equal(clock.Now().Add(30 * time.Minute)) {
+		t.Error("Invite should last as long as the seat is held", inviteResponse.Expires)
+	}
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: "foobar"}
+	readResponse(serveTestRequest(gameManager.Game, getReq).Result(), &gameResponse)
+	if gameResponse.Reservations[2].Name != "alice" {
+		t.Fatal("Seat 2 should be held for alice", gameResponse.Reservations)
+	}
+
+	recorder = serveTestRequest(gameManager.Join, JoinRequest{Invite: inviteResponse.Invite})
+	checkResponse(t, "join", recorder)
+	if strings.Contains(recorder.Body.String(), "foobar") {
+		t.Error("Joining doesn't give away the passphrase")
+	}
+	join := JoinResponse{}
+	readResponse(recorder.Result(), &join)
+	if join.Game.GameID != gameResponse.GameID || join.Seat == nil || *join.Seat != 2 || join.Name != "alice" {
+		t.Fatal(join)
+	}
+
+	// The invite goes where the passphrase would
+	playerReq := AddPlayerRequest{Name: join.Name, Seat: *join.Seat, Passphrase: inviteResponse.Invite, GameID: join.Game.GameID}
+	if recorder := serveTestRequest(gameManager.AddPlayer, playerReq); recorder.Code != http.StatusConflict {
+		t.Error("Going by the name doesn't take the seat held for alice", recorder.Code)
+	}
+	playerReq.Name = "bob"
+	playerReq.Claim = join.Claim
+	if recorder := serveTestRequest(gameManager.AddPlayer, playerReq); recorder.Code != http.StatusOK {
+		t.Error(recorder.Body.String())
+	}
+	getReq.Passphrase = inviteResponse.Invite
+	if recorder := serveTestRequest(gameManager.Game, getReq); recorder.Code != http.StatusOK {
+		t.Error(recorder.Body.String())
+	}
+	otherGame := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &otherGame)
+	if recorder := serveTestRequest(gameManager.Game, GetGameRequest{GameID: otherGame.GameID, Passphrase: inviteResponse.Invite}); recorder.Code != http.StatusBadRequest {
+		t.Error("Invites are only good for their own game", recorder.Code)
+	}
+
+	// Invites don't need a seat, and can't hold one which is taken
+	inviteReq.Seat = nil
+	readResponse(serveTestRequest(gameManager.Invite, inviteReq).Result(), &inviteResponse)
+	join = JoinResponse{}
+	readResponse(serveTestRequest(gameManager.Join, JoinRequest{Invite: inviteResponse.Invite}).Result(), &join)
+	if join.Seat != nil {
+		t.Error(join)
+	}
+	inviteReq.Seat = &seat
+	if recorder := serveTestRequest(gameManager.Invite, inviteReq); recorder.Code != http.StatusConflict {
+		t.Error("Seat 2 is taken", recorder.Code)
+	}
+	inviteReq.Seat = nil
+	for _, passphrase := range []string{"wrong", inviteResponse.Invite} {
+		inviteReq.Passphrase = passphrase
+		if recorder := serveTestRequest(gameManager.Invite, inviteReq); recorder.Code != http.StatusBadRequest {
+			t.Error("Invites need the passphrase", recorder.Code)
+		}
+	}
+
+	otherManager := NewGameManager()
+	forged, _ := otherManager.signInvite(invite{GameID: gameResponse.GameID, Expires: clock.Now().Add(time.Hour).Unix()})
+	expired, _ := gameManager.signInvite(invite{GameID: gameResponse.GameID, Expires: clock.Now().Add(-time.Minute).Unix()})
+	parts := strings.Split(inviteResponse.Invite, ".")
+	changed, _ := gameManager.signInvite(invite{GameID: "somewhere", Expires: clock.Now().Add(time.Hour).Unix()})
+	changed = strings.Split(changed, ".")[0] + "." + parts[1]
+	for _, token := range []string{forged, expired, changed, "", "not.an.invite"} {
+		recorder := serveTestRequest(gameManager.Join, JoinRequest{Invite: token})
+		response := ErrorResponse{}
+		readResponse(recorder.Result(), &response)
+		if recorder.Code != http.StatusForbidden || response.Code != CodeInvalidInvite {
+			t.Error(token, recorder.Code, response)
+		}
+	}
+
+	// Invites go by the manager's clock, like the seats they hold
+	clock.advance(30 * time.Minute)
+	if recorder := serveTestRequest(gameManager.Join, JoinRequest{Invite: inviteResponse.Invite}); recorder.Code != http.StatusForbidden {
+		t.Error("Invite has expired", recorder.Code)
+	}
+	inviteReq.Passphrase = "foobar"
+	readResponse(serveTestRequest(gameManager.Invite, inviteReq).Result(), &inviteResponse)
+
+	gameManager.CloseGame(gameResponse.GameID)
+	if recorder := serveTestRequest(gameManager.Join, JoinRequest{Invite: inviteResponse.Invite}); recorder.Code != http.StatusNotFound {
+		t.Error("Game is over", recorder.Code)
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+type ledgerResponse struct {
+	GameID    string              `json:"gameID"`
+	Entries   []poker.LedgerEntry `json:"entries"`
+	Transfers []poker.Transfer    `json:"transfers"`
+	// Rake is what the house took, which is why the nets add up to less than 0. The transfers
+	// pay it to the house, at poker.HouseSeat
+	Rake int `json:"rake"`
+}
+
+// Ledger gets what everyone has bought in for and cashed out with, and who pays whom to settle up
+func (manager *GameManager) Ledger(w http.ResponseWriter, r *http.Request) {
+	ledger, ok := manager.resolveLedger(w, r)
+	if !ok {
+		return
+	}
+
+	sendJSONResponse(w, r, ledger)
+}
+
+// LedgerCSV is the ledger as a CSV download, with one row for each player
+func (manager *GameManager) LedgerCSV(w http.ResponseWriter, r *http.Request) {
+	ledger, ok := manager.resolveLedger(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"game-%s-ledger.csv\"", ledger.GameID))
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"Seat", "Name", "Buy In", "Top Ups", "Cash Out", "Net", "Pays"})
+	for _, entry := range ledger.Entries {
+		pays := make([]string, 0)
+		for _, transfer := range ledger.Transfers {
+			if transfer.FromPlayer == entry.Player {
+				pays = append(pays, fmt.Sprintf("%d to %s", transfer.Amount, transfer.ToName))
+			}
+		}
+
+		writer.Write([]string{
+			strconv.Itoa(entry.Seat),
+			entry.Name,
+			strconv.Itoa(entry.BuyIn),
+			strconv.Itoa(entry.TopUps),
+			strconv.Itoa(entry.CashOut),
+			strconv.Itoa(entry.Net),
+			strings.Join(pays, "; "),
+		})
+	}
+	if ledger.Rake > 0 {
+		writer.Write([]string{"", "Rake", "", "", "", strconv.Itoa(ledger.Rake), ""})
+	}
+	writer.Flush()
+}
+
+func (manager *GameManager) resolveLedger(w http.ResponseWriter, r *http.Request) (ledgerResponse, bool) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return ledgerResponse{}, false
+	}
+
+	get := GetGameRequest{}
+	if ok := decodeJSONBody(w, r, &get); !ok {
+		return ledgerResponse{}, false
+	}
+
+	loop, err := manager.findGame(get.GameID, get.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return ledgerResponse{}, false
+	}
+
+	ledger := ledgerResponse{GameID: get.GameID}
+	ok := loop.do(w, r, func(game *poker.Game) {
+		ledger.Entries = game.Ledger()
+		ledger.Transfers = game.Settle()
+		ledger.Rake = game.RakeCollected()
+	})
+	return ledger, ok
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/csv"
+	"testing"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func TestLedgerApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	players := make(map[int]AddPlayerResponse)
+	for _, seat := range []int{0, 1} {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		players[seat] = playerResponse
+	}
+
+	// Heads up the button folds the small blind
+	startReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	serveTestRequest(gameManager.StartHand, startReq)
+	actReq := ActRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	actReq.Action = poker.Action{Type: poker.Fold}
+	serveTestRequest(gameManager.Act, actReq)
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	ledger := ledgerResponse{}
+	readResponse(serveTestRequest(gameManager.Ledger, getReq).Result(), &ledger)
+	if len(ledger.Entries) != 2 || ledger.Entries[0].Net != -5 || len(ledger.Transfers) != 1 || ledger.Transfers[0].Amount != 5 {
+		t.Error(ledger)
+	}
+
+	recorder := serveTestRequest(gameManager.LedgerCSV, getReq)
+	if recorder.Header().Get("Content-Type") != "text/csv" {
+		t.Error()
+	}
+	rows, err := csv.NewReader(recorder.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[1][5] != "-5" || rows[1][6] != "5 to foo" {
+		t.Error(rows)
+	}
+
+	// Someone else sitting down in the same seat with the same name doesn't owe what the first
+	// player did
+	leaveReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	serveTestRequest(gameManager.LeaveTable, leaveReq)
+	playerReq := AddPlayerRequest{Name: "foo", Seat: 0, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+	serveTestRequest(gameManager.AddPlayer, playerReq)
+	rows, err = csv.NewReader(serveTestRequest(gameManager.LedgerCSV, getReq).Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[1][5] != "0" || rows[1][6] != "" || rows[3][5] != "-5" || rows[3][6] != "5 to foo" {
+		t.Error(rows)
+	}
+}
+
+func TestLedgerRakeApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, Rake: poker.Rake{Percent: 20}}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	players := make(map[int]AddPlayerResponse)
+	for _, seat := range []int{0, 1} {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		players[seat] = playerResponse
+	}
+
+	// The pot of 15 is raked 3, so the big blind only wins 2
+	startReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	serveTestRequest(gameManager.StartHand, startReq)
+	actReq := ActRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: players[0].Secret}
+	actReq.Action = poker.Action{Type: poker.Fold}
+	serveTestRequest(gameManager.Act, actReq)
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	ledger := ledgerResponse{}
+	readResponse(serveTestRequest(gameManager.Ledger, getReq).Result(), &ledger)
+	if ledger.Rake != 3 || ledger.Entries[1].Net != 2 || len(ledger.Transfers) != 2 {
+		t.Fatal(ledger)
+	}
+	// The small blind pays the house the rake, so everything they lost is paid to someone
+	paid := make(map[int]int)
+	for _, transfer := range ledger.Transfers {
+		if transfer.FromSeat != 0 {
+			t.Error(transfer)
+		}
+		paid[transfer.ToSeat] += transfer.Amount
+	}
+	if paid[1] != 2 || paid[poker.HouseSeat] != 3 {
+		t.Error(ledger.Transfers)
+	}
+
+	rows, err := csv.NewReader(serveTestRequest(gameManager.LedgerCSV, getReq).Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[3][1] != "Rake" || rows[3][5] != "3" {
+		t.Error(rows)
+	}
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+const (
+	// DefaultLobbyPageSize is how many games are on a page of the lobby when it isn't asked for
+	DefaultLobbyPageSize = 20
+	// MaxLobbyPageSize is the most games which can be on a page of the lobby
+	MaxLobbyPageSize = 100
+)
+
+type lobbyGame struct {
+	GameID      string         `json:"gameID"`
+	CurrentGame poker.GameType `json:"currentGame"`
+	BlindSize   int            `json:"blindSize"`
+	Blinds      poker.Blinds   `json:"blinds"`
+	TableSize   int            `json:"tableSize"`
+	SeatsFilled int            `json:"seatsFilled"`
+	EmptySeats  []int          `json:"emptySeats"`
+	Waitlist    int            `json:"waitlist"`
+	// PassphraseRequired is false for games anyone in the lobby can sit down at
+	PassphraseRequired bool             `json:"passphraseRequired"`
+	Stats              poker.TableStats `json:"stats"`
+	Created            time.Time        `json:"created"`
+}
+
+type lobbyResponse struct {
+	Games []lobbyGame `json:"games"`
+	// Total is how many games match the filters, across every page
+	Total    int `json:"total"`
+	Page     int `json:"page"`
+	PageSize int `json:"pageSize"`
+}
+
+// lobbyFilter is what a lobby request narrows the games down to. Filters left out of the query
+// match every game
+type lobbyFilter struct {
+	variant   *poker.Variant
+	betting   *poker.BettingStructure
+	minBlind  int
+	maxBlind  int
+	openSeats bool
+	page      int
+	pageSize  int
+}
+
+func (filter *lobbyFilter) matches(game lobbyGame) bool {
+	if filter.variant != nil && game.CurrentGame.Variant != *filter.variant {
+		return false
+	}
+	if filter.betting != nil && game.CurrentGame.Betting != *filter.betting {
+		return false
+	}
+	if game.BlindSize < filter.minBlind || (filter.maxBlind > 0 && game.BlindSize > filter.maxBlind) {
+		return false
+	}
+	return !filter.openSeats || len(game.EmptySeats) > 0
+}
+
+// Lobby lists the games which aren't private, so players can find one without being told its
+// id. It takes the query parameters:
+//
+//	variant    only games currently dealing this variant, i.e. holdem
+//	betting    only games currently using this betting structure, i.e. no-limit
+//	minBlind   only games with at least this big blind
+//	maxBlind   only games with at most this big blind
+//	openSeats  only games with an empty seat, when true
+//	page       which page of games, starting from 1
+//	pageSize   how many games are on a page
+func (manager *GameManager) Lobby(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w, r, "GET")
+		return
+	}
+
+	filter, err := parseLobbyFilter(r.URL.Query())
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	manager.gamesLock.RLock()
+	loops := make([]*gameLoop, 0, len(manager.games))
+	for _, loop := range manager.games {
+		if !loop.private {
+			loops = append(loops, loop)
+		}
+	}
+	manager.gamesLock.RUnlock()
+
+	games := make([]lobbyGame, 0, len(loops))
+	for _, loop := range loops {
+		var game lobbyGame
+		err := loop.run(func(g *poker.Game) {
+			game = newLobbyGame(loop, g)
+		})
+		if err == nil && filter.matches(game) {
+			games = append(games, game)
+		}
+	}
+	// Oldest games come first, so pages don't shift around as games are created
+	sort.Slice(games, func(i, j int) bool {
+		if !games[i].Created.Equal(games[j].Created) {
+			return games[i].Created.Before(games[j].Created)
+		}
+		return games[i].GameID < games[j].GameID
+	})
+
+	response := lobbyResponse{Games: make([]lobbyGame, 0), Total: len(games), Page: filter.page, PageSize: filter.pageSize}
+	// Pages past the last one are checked before working out where they start, which could
+	// overflow for a big enough page
+	if pages := (len(games) + filter.pageSize - 1) / filter.pageSize; filter.page <= pages {
+		start := (filter.page - 1) * filter.pageSize
+		end := start + filter.pageSize
+		if end > len(games) {
+			end = len(games)
+		}
+		response.Games = games[start:end]
+	}
+
+	sendJSONResponse(w, r, response)
+}
+
+func newLobbyGame(loop *gameLoop, game *poker.Game) lobbyGame {
+	return lobbyGame{
+		GameID:             loop.gameID,
+		CurrentGame:        game.CurrentGame(),
+		BlindSize:          game.BlindSize(),
+		Blinds:             game.Blinds(),
+		TableSize:          game.TableSize(),
+		SeatsFilled:        len(game.Players()),
+		EmptySeats:         game.EmptySeats(),
+		Waitlist:           len(game.Waitlist()),
+		PassphraseRequired: loop.passphrase != "",
+		Stats:              game.Stats(),
+		Created:            loop.created,
+	}
+}
+
+func parseLobbyFilter(query url.Values) (lobbyFilter, error) {
+	filter := lobbyFilter{page: 1, pageSize: DefaultLobbyPageSize}
+
+	if name := query.Get("variant"); name != "" {
+		variant := poker.Variant(0)
+		if err := variant.UnmarshalText([]byte(name)); err != nil {
+			return filter, newRequestError(CodeInvalidField, "variant", "%s", err)
+		}
+		filter.variant = &variant
+	}
+	if name := query.Get("betting"); name != "" {
+		betting := poker.BettingStructure(0)
+		if err := betting.UnmarshalText([]byte(name)); err != nil {
+			return filter, newRequestError(CodeInvalidField, "betting", "%s", err)
+		}
+		filter.betting = &betting
+	}
+
+	numbers := []struct {
+		name string
+		dst  *int
+		min  int
+	}{
+		{"minBlind", &filter.minBlind, 0},
+		{"maxBlind", &filter.maxBlind, 0},
+		{"page", &filter.page, 1},
+		{"pageSize", &filter.pageSize, 1},
+	}
+	for _, number := range numbers {
+		value := query.Get(number.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < number.min {
+			return filter, newRequestError(CodeInvalidField, number.name, "%s must be a number of at least %d", number.name, number.min)
+		}
+		*number.dst = parsed
+	}
+	if filter.pageSize > MaxLobbyPageSize {
+		filter.pageSize = MaxLobbyPageSize
+	}
+
+	if value := query.Get("openSeats"); value != "" {
+		openSeats, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, newRequestError(CodeInvalidField, "openSeats", "openSeats must be true or false")
+		}
+		filter.openSeats = openSeats
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func serveLobbyRequest(gameManager *GameManager, query string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	gameManager.Lobby(recorder, httptest.NewRequest("GET", "/api/v1/lobby?"+query, nil))
+	return recorder
+}
+
+func TestLobbyApi(t *testing.T) {
+	gameManager := NewGameManager()
+	games := []CreateGameRequest{
+		{Passphrase: "", StarterChips: 100, BlindSize: 10},
+		{Passphrase: "foobar", StarterChips: 100, BlindSize: 20, BettingStructure: poker.PotLimit},
+		{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, Private: true},
+		{Passphrase: "foobar", StarterChips: 100, BlindSize: 40, TableSize: 2},
+	}
+	ids := make([]string, 0)
+	for _, gameReq := range games {
+		gameResponse := GetGameResponse{}
+		readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+		ids = append(ids, gameResponse.GameID)
+	}
+
+	// Game 3 is full, and has played a hand which everyone folded
+	secrets := make([]int, 0)
+	for seat := 0; seat < 2; seat++ {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: "foobar", GameID: ids[3]}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		secrets = append(secrets, playerResponse.Secret)
+	}
+	handReq := PlayerRequest{GameID: ids[3], Passphrase: "foobar", Seat: 0, Secret: secrets[0]}
+	hand := poker.HandView{}
+	readResponse(serveTestRequest(gameManager.StartHand, handReq).Result(), &hand)
+	actReq := ActRequest{GameID: ids[3], Passphrase: "foobar", Seat: hand.ToAct, Secret: secrets[hand.ToAct], Action: poker.Action{Type: poker.Fold}}
+	serveTestRequest(gameManager.Act, actReq)
+
+	recorder := serveLobbyRequest(&gameManager, "")
+	checkResponse(t, "lobby", recorder)
+	lobby := lobbyResponse{}
+	readResponse(recorder.Result(), &lobby)
+	if lobby.Total != 3 || len(lobby.Games) != 3 || lobby.Games[2].GameID != ids[3] {
+		t.Fatal("Private games aren't listed", lobby)
+	}
+	if !lobby.Games[1].PassphraseRequired || lobby.Games[0].PassphraseRequired {
+		t.Error("Only game 0 has no passphrase")
+	}
+	full := lobby.Games[2]
+	if full.SeatsFilled != 2 || len(full.EmptySeats) != 0 || full.Stats.Hands != 1 || full.Stats.AveragePot != 60 || full.Stats.PlayersPerFlop != 0 {
+		t.Error(full)
+	}
+
+	filters := []struct {
+		query string
+		games []int
+	}{
+		{"betting=pot-limit", []int{1}},
+		{"variant=holdem&betting=no-limit", []int{0, 3}},
+		{"minBlind=20", []int{1, 3}},
+		{"minBlind=15&maxBlind=30", []int{1}},
+		{"openSeats=true", []int{0, 1}},
+		{"pageSize=2", []int{0, 1}},
+		{"pageSize=2&page=2", []int{3}},
+		{"page=3", []int{}},
+		{"page=4611686018427387904&pageSize=4", []int{}},
+	}
+	for _, filter := range filters {
+		lobby := lobbyResponse{}
+		readResponse(serveLobbyRequest(&gameManager, filter.query).Result(), &lobby)
+		if len(lobby.Games) != len(filter.games) {
+			t.Error(filter.query, lobby.Games)
+			continue
+		}
+		for i, game := range lobby.Games {
+			if game.GameID != ids[filter.games[i]] {
+				t.Error(filter.query, lobby.Games)
+			}
+		}
+	}
+
+	for _, query := range []string{"variant=bingo", "page=0", "minBlind=lots", "openSeats=maybe"} {
+		if recorder := serveLobbyRequest(&gameManager, query); recorder.Code != http.StatusBadRequest {
+			t.Error(query, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package api
+
+import (
+	"encoding"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// OpenAPIPath is where the OpenAPI document for the API is served
+const OpenAPIPath = "/api/openapi.json"
+
+// openAPIDocument is an OpenAPI 3 document. Only the parts of the spec the API uses are here
+type openAPIDocument struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       openAPIInfo                     `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components openAPIComponents               `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+type operation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []parameter                `json:"parameters,omitempty"`
+	RequestBody *requestBody               `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+// schema is a JSON schema as OpenAPI 3.0 uses it
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	OneOf                []*schema          `json:"oneOf,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	AdditionalProperties *schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// either is a response which can be one of a few structs
+type either []interface{}
+
+// route is an endpoint of the API. Request and response are values of the structs the
+// handler decodes and encodes, which the schemas are generated from
+type route struct {
+	path     string
+	method   string
+	id       string
+	summary  string
+	tag      string
+	params   []parameter
+	request  interface{}
+	response interface{}
+	// contentType is for responses which aren't JSON
+	contentType string
+	status      int
+}
+
+// entrantResponse is the hand at the entrant's table, or the whole tournament once they
+// don't have a table
+var entrantResponse = either{poker.HandView{}, getTournamentResponse{}}
+
+var (
+	gameIDParam     = parameter{Name: "gameID", In: "path", Required: true, Schema: &schema{Type: "string"}}
+	seatParam       = parameter{Name: "seat", In: "path", Required: true, Schema: &schema{Type: "integer"}}
+	passphraseParam = parameter{Name: passphraseHeader, In: "header", Required: true, Schema: &schema{Type: "string"}}
+	secretParam     = parameter{Name: secretHeader, In: "header", Required: true, Schema: &schema{Type: "integer"}}
+)
+
+// routes is every endpoint cmd/main.go serves. New endpoints need to be added here so they are
+// in the published spec
+var routes = []route{
+	{path: "/api/v1/game/status", method: "POST", id: "getGame", summary: "Get a game", tag: "game",
+		request: GetGameRequest{}, response: GetGameResponse{}},
+	{path: "/api/v1/game/create", method: "POST", id: "createGame", summary: "Create a game", tag: "game",
+		request: CreateGameRequest{}, response: CreateGameResponse{}},
+	{path: "/api/v1/game/add-player", method: "POST", id: "addPlayer", summary: "Sit a player down", tag: "game",
+		request: AddPlayerRequest{}, response: AddPlayerResponse{}},
+	{path: "/api/v1/game/choose-game", method: "POST", id: "chooseGame", summary: "Choose the game for dealer's choice", tag: "game",
+		request: chooseGameRequest{}, response: GetGameResponse{}},
+	{path: "/api/v1/game/start-hand", method: "POST", id: "startHand", summary: "Deal the next hand", tag: "game",
+		request: PlayerRequest{}, response: poker.HandView{}},
+	{path: "/api/v1/game/hand", method: "POST", id: "getHand", summary: "Get the hand as a player sees it", tag: "game",
+		request: PlayerRequest{}, response: poker.HandView{}},
+	{path: "/api/v1/game/act", method: "POST", id: "act", summary: "Take an action in the hand", tag: "game",
+		request: ActRequest{}, response: poker.HandView{}},
+	{path: "/api/v1/game/straddle", method: "POST", id: "straddle", summary: "Straddle the next hand", tag: "game",
+		request: straddleRequest{}, response: GetGameResponse{}},
+	{path: "/api/v1/game/run-it", method: "POST", id: "runIt", summary: "Vote on how many times to run it", tag: "game",
+		request: runItRequest{}, response: poker.HandView{}},
+	{path: "/api/v1/game/bomb-pot", method: "POST", id: "bombPot", summary: "Vote for a bomb pot", tag: "game",
+		request: bombPotRequest{}, response: GetGameResponse{}},
+	{path: "/api/v1/game/rebuy", method: "POST", id: "rebuy", summary: "Buy more chips", tag: "game",
+		request: PlayerRequest{}, response: GetGameResponse{}},
+	{path: "/api/v1/game/sit-out", method: "POST", id: "sitOut", summary: "Sit out or come back", tag: "game",
+		request: SitOutRequest{}, response: GetGameResponse{}},
+	{path: "/api/v1/game/leave", method: "POST", id: "leaveTable", summary: "Leave the table and cash out", tag: "game",
+		request: PlayerRequest{}, response: poker.LedgerEntry{}},
+	{path: "/api/v1/game/reserve-seat", method: "POST", id: "reserveSeat", summary: "Hold a seat for someone", tag: "game",
+		request: reserveSeatRequest{}, response: reserveSeatResponse{}},
+	{path: "/api/v1/game/waitlist", method: "POST", id: "waitlist", summary: "Join or leave the waitlist", tag: "game",
+		request: waitlistRequest{}, response: waitlistResponse{}},
+	{path: "/api/v1/game/events", method: "GET", id: "gameEvents", summary: "Stream the game's events", tag: "game",
+		params: []parameter{
+			{Name: "gameID", In: "query", Required: true, Schema: &schema{Type: "string"}},
+			{Name: "passphrase", In: "query", Required: true, Schema: &schema{Type: "string"}},
+		},
+		contentType: "text/event-stream"},
+	{path: "/api/v1/game/ledger", method: "POST", id: "ledger", summary: "Get the ledger", tag: "game",
+		request: GetGameRequest{}, response: ledgerResponse{}},
+	{path: "/api/v1/game/ledger.csv", method: "POST", id: "ledgerCSV", summary: "Download the ledger", tag: "game",
+		request: GetGameRequest{}, contentType: "text/csv"},
+	{path: "/api/v1/game/invite", method: "POST", id: "invite", summary: "Make an invite link, holding a seat for who is invited", tag: "game",
+		request: InviteRequest{}, response: InviteResponse{}},
+	{path: "/api/v1/game/join", method: "POST", id: "join", summary: "Get into a game with an invite", tag: "game",
+		request: JoinRequest{}, response: JoinResponse{}},
+	{path: "/api/v1/game/spectate", method: "POST", id: "spectate", summary: "Start watching a game without a seat", tag: "game",
+		request: SpectateRequest{}, response: SpectateResponse{}},
+	{path: "/api/v1/game/watch", method: "POST", id: "watch", summary: "Get the game as a spectator sees it", tag: "game",
+		request: WatchRequest{}, response: GetGameResponse{}},
+	{path: "/api/v1/game/spectator-rules", method: "POST", id: "spectatorRules", summary: "Turn spectating on or off", tag: "game",
+		request: spectatorRulesRequest{}, response: GetGameResponse{}},
+
+	{path: "/api/v1/lobby", method: "GET", id: "lobby", summary: "List the games which aren't private", tag: "lobby",
+		params: []parameter{
+			{Name: "variant", In: "query", Schema: &schema{Type: "string", Enum: enumNames(reflect.TypeOf(poker.HoldEm))}},
+			{Name: "betting", In: "query", Schema: &schema{Type: "string", Enum: enumNames(reflect.TypeOf(poker.NoLimit))}},
+			{Name: "minBlind", In: "query", Schema: &schema{Type: "integer"}},
+			{Name: "maxBlind", In: "query", Schema: &schema{Type: "integer"}},
+			{Name: "openSeats", In: "query", Schema: &schema{Type: "boolean"}},
+			{Name: "page", In: "query", Schema: &schema{Type: "integer"}},
+			{Name: "pageSize", In: "query", Schema: &schema{Type: "integer"}},
+		},
+		response: lobbyResponse{}},
+
+	{path: v2GamesPath, method: "POST", id: "createGameV2", summary: "Create a game", tag: "games",
+		request: CreateGameRequest{}, response: CreateGameResponse{}, status: http.StatusCreated},
+	{path: v2GamesPath + "/{gameID}", method: "GET", id: "getGameV2", summary: "Get a game", tag: "games",
+		params: []parameter{gameIDParam, passphraseParam}, response: GetGameResponse{}},
+	{path: v2GamesPath + "/{gameID}/players", method: "POST", id: "addPlayerV2", summary: "Sit a player down", tag: "games",
+		params: []parameter{gameIDParam, passphraseParam}, request: addPlayerV2Request{}, response: AddPlayerResponse{},
+		status: http.StatusCreated},
+	{path: v2GamesPath + "/{gameID}/players/{seat}", method: "DELETE", id: "removePlayerV2", summary: "Leave the table and cash out", tag: "games",
+		params: []parameter{gameIDParam, seatParam, passphraseParam, secretParam}, response: poker.LedgerEntry{}},
+	{path: v2GamesPath + "/{gameID}/actions", method: "POST", id: "actV2", summary: "Take an action in the hand", tag: "games",
+		params: []parameter{gameIDParam, passphraseParam, secretParam}, request: actV2Request{}, response: poker.HandView{}},
+
+	{path: "/api/v1/tournament/status", method: "POST", id: "getTournament", summary: "Get a tournament", tag: "tournament",
+		request: getTournamentRequest{}, response: getTournamentResponse{}},
+	{path: "/api/v1/tournament/create", method: "POST", id: "createTournament", summary: "Create a tournament", tag: "tournament",
+		request: createTournamentRequest{}, response: getTournamentResponse{}},
+	{path: "/api/v1/tournament/register", method: "POST", id: "register", summary: "Register for a tournament", tag: "tournament",
+		request: registerRequest{}, response: registerResponse{}},
+	{path: "/api/v1/tournament/start", method: "POST", id: "startTournament", summary: "Seat everyone who has registered", tag: "tournament",
+		request: getTournamentRequest{}, response: getTournamentResponse{}},
+	{path: "/api/v1/tournament/start-hand", method: "POST", id: "startTournamentHand", summary: "Deal the next hand at the entrant's table", tag: "tournament",
+		request: entrantRequest{}, response: entrantResponse},
+	{path: "/api/v1/tournament/act", method: "POST", id: "tournamentAct", summary: "Take an action at the entrant's table", tag: "tournament",
+		request: entrantRequest{}, response: entrantResponse},
+	{path: "/api/v1/tournament/rebuy", method: "POST", id: "tournamentRebuy", summary: "Rebuy", tag: "tournament",
+		request: entrantRequest{}, response: entrantResponse},
+	{path: "/api/v1/tournament/add-on", method: "POST", id: "tournamentAddOn", summary: "Take the add-on", tag: "tournament",
+		request: entrantRequest{}, response: entrantResponse},
+	{path: "/api/v1/tournament/re-enter", method: "POST", id: "tournamentReEnter", summary: "Re-enter after being knocked out", tag: "tournament",
+		request: entrantRequest{}, response: entrantResponse},
+
+	{path: "/api/v1/payouts", method: "POST", id: "payouts", summary: "Split a prize pool by finishing place", tag: "tools",
+		request: payoutsRequest{}, response: payoutsResponse{}},
+	{path: "/api/v1/icm", method: "POST", id: "icm", summary: "Work out each stack's ICM equity", tag: "tools",
+		request: icmRequest{}, response: icmResponse{}},
+}
+
+// openAPI is built once, since the routes and structs can't change while the server runs
+var openAPI = newOpenAPIDocument(routes)
+
+// OpenAPI serves the OpenAPI document describing the API
+func OpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w, r, "GET")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	sendJSONResponse(w, r, openAPI)
+}
+
+func newOpenAPIDocument(routes []route) openAPIDocument {
+	schemas := newSchemaGenerator()
+	errorSchema := schemas.schemaOf(reflect.TypeOf(ErrorResponse{}))
+
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "httpoker",
+			Description: "Host home poker games and tournaments. Every error responds with an ErrorResponse",
+			Version:     "1.0.0",
+		},
+		Paths: make(map[string]map[string]operation),
+	}
+
+	for _, route := range routes {
+		op := operation{
+			OperationID: route.id,
+			Summary:     route.summary,
+			Tags:        []string{route.tag},
+			Parameters:  route.params,
+			Responses: map[string]openAPIResponse{
+				"default": {
+					Description: "Error",
+					Content:     map[string]mediaType{"application/json": {Schema: errorSchema}},
+				},
+			},
+		}
+
+		if route.request != nil {
+			op.RequestBody = &requestBody{
+				Required: true,
+				Content:  map[string]mediaType{"application/json": {Schema: schemas.schemaOf(reflect.TypeOf(route.request))}},
+			}
+		}
+
+		status := route.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := openAPIResponse{Description: http.StatusText(status)}
+		switch {
+		case route.contentType != "":
+			success.Content = map[string]mediaType{route.contentType: {Schema: &schema{Type: "string"}}}
+		case route.response != nil:
+			success.Content = map[string]mediaType{"application/json": {Schema: schemas.responseSchema(route.response)}}
+		}
+		op.Responses[strconv.Itoa(status)] = success
+
+		if doc.Paths[route.path] == nil {
+			doc.Paths[route.path] = make(map[string]operation)
+		}
+		doc.Paths[route.path][strings.ToLower(route.method)] = op
+	}
+
+	doc.Components.Schemas = schemas.components
+	return doc
+}
+
+func (gen *schemaGenerator) responseSchema(response interface{}) *schema {
+	alternatives, ok := response.(either)
+	if !ok {
+		return gen.schemaOf(reflect.TypeOf(response))
+	}
+
+	oneOf := &schema{}
+	for _, alternative := range alternatives {
+		oneOf.OneOf = append(oneOf.OneOf, gen.schemaOf(reflect.TypeOf(alternative)))
+	}
+	return oneOf
+}
+
+// schemaGenerator makes schemas from go types, using the same rules encoding/json does to
+// serialize them. Structs become components which are referenced by name
+type schemaGenerator struct {
+	components map[string]*schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		components: make(map[string]*schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	cardType          = reflect.TypeOf(poker.Card{})
+)
+
+func (gen *schemaGenerator) schemaOf(t reflect.Type) *schema {
+	// Pointers are checked first, since they have the methods of what they point to
+	if t.Kind() == reflect.Ptr {
+		elem := gen.schemaOf(t.Elem())
+		if elem.Ref != "" {
+			// Nothing can sit next to a $ref, so it is wrapped to be nullable
+			return &schema{AllOf: []*schema{elem}, Nullable: true}
+		}
+		elem.Nullable = true
+		return elem
+	}
+
+	switch {
+	case t == timeType:
+		return &schema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &schema{Type: "integer", Format: "int64", Description: "Nanoseconds"}
+	case t == cardType:
+		return &schema{Type: "string", Pattern: "^[2-9TJQKAX][SHDC]$", Description: "Value then suit, i.e. AS for the ace of spades. X is a joker"}
+	case t.Implements(textMarshalerType):
+		return &schema{Type: "string", Enum: enumNames(t)}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return &schema{Ref: "#/components/schemas/" + gen.component(t)}
+	case reflect.Slice, reflect.Array:
+		return &schema{Type: "array", Items: gen.schemaOf(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		// Map keys are always strings in JSON, even when they are seat numbers
+		return &schema{Type: "object", AdditionalProperties: gen.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	}
+	return &schema{}
+}
+
+// component adds the schema for a struct to the components, and gives its name
+func (gen *schemaGenerator) component(t reflect.Type) string {
+	if name, ok := gen.names[t]; ok {
+		return name
+	}
+
+	name := exported(t.Name())
+	if _, taken := gen.components[name]; taken {
+		// Two packages have a struct by the same name, so the package tells them apart
+		name = exported(t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]) + name
+	}
+	gen.names[t] = name
+
+	// The component is added before its fields so types which refer to themselves terminate
+	component := &schema{Type: "object", Properties: make(map[string]*schema)}
+	gen.components[name] = component
+	for _, field := range jsonFields(t) {
+		component.Properties[field.name] = gen.schemaOf(field.typ)
+		if !field.omitEmpty {
+			component.Required = append(component.Required, field.name)
+		}
+	}
+	return name
+}
+
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+// jsonFields are the fields of a struct encoding/json serializes, by the name it uses
+func jsonFields(t reflect.Type) []jsonField {
+	fields := make([]jsonField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.Index(tag, ","); comma != -1 {
+			name, options = tag[:comma], tag[comma:]
+		}
+		// Like encoding/json, the fields of an embedded struct are serialized as if they were
+		// the outer struct's
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonField{name: name, typ: field.Type, omitEmpty: strings.Contains(options, ",omitempty")})
+	}
+	return fields
+}
+
+// enumNames finds the names of an enum which is serialized as text, by counting up from zero
+// until a value doesn't serialize
+func enumNames(t reflect.Type) []string {
+	if t.Kind() != reflect.Int {
+		return nil
+	}
+
+	names := make([]string, 0)
+	for i := 0; ; i++ {
+		value := reflect.New(t).Elem()
+		value.SetInt(int64(i))
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return names
+		}
+		names = append(names, string(text))
+	}
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// checkSchema makes sure a decoded JSON value has exactly the shape the spec says, so a field
+// added to or removed from a struct without the spec changing is caught
+func checkSchema(s *schema, value interface{}, path string) error {
+	if s.Ref != "" {
+		return checkSchema(openAPI.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")], value, path)
+	}
+	if value == nil {
+		if s.Nullable || s.Type == "" && len(s.AllOf) == 0 && len(s.OneOf) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%s is null", path)
+	}
+	for _, all := range s.AllOf {
+		if err := checkSchema(all, value, path); err != nil {
+			return err
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, one := range s.OneOf {
+			if checkSchema(one, value, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s matches %d schemas, not one", path, matches)
+		}
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not an object", path)
+		}
+		for _, required := range s.Required {
+			if _, ok := object[required]; !ok {
+				return fmt.Errorf("%s.%s is missing", path, required)
+			}
+		}
+		for key, property := range object {
+			propertySchema := s.AdditionalProperties
+			if s.Properties != nil {
+				propertySchema = s.Properties[key]
+			}
+			if propertySchema == nil {
+				return fmt.Errorf("%s.%s is not in the spec", path, key)
+			}
+			if err := checkSchema(propertySchema, property, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not an array", path)
+		}
+		for i, item := range array {
+			if err := checkSchema(s.Items, item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s is not a string", path)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			return fmt.Errorf("%s is %q, which is not one of %v", path, str, s.Enum)
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(str) {
+			return fmt.Errorf("%s is %q, which does not match %s", path, str, s.Pattern)
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return fmt.Errorf("%s is not an integer", path)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s is not a number", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s is not a boolean", path)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func findRoute(t *testing.T, id string) route {
+	for _, route := range routes {
+		if route.id == id {
+			return route
+		}
+	}
+	t.Fatalf("No route %s", id)
+	return route{}
+}
+
+// checkResponse makes sure a handler's response is what the spec says the route responds with
+func checkResponse(t *testing.T, id string, recorder *httptest.ResponseRecorder) {
+	t.Helper()
+	route := findRoute(t, id)
+	op := openAPI.Paths[route.path][strings.ToLower(route.method)]
+
+	status := strconv.Itoa(recorder.Code)
+	response, ok := op.Responses[status]
+	if !ok {
+		response = op.Responses["default"]
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &value); err != nil {
+		t.Fatalf("%s: %v", id, err)
+	}
+	if err := checkSchema(response.Content["application/json"].Schema, value, id); err != nil {
+		t.Errorf("%s responded with %s: %v", id, status, err)
+	}
+}
+
+func TestOpenAPIApi(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	OpenAPI(recorder, httptest.NewRequest("GET", OpenAPIPath, nil))
+	doc := openAPIDocument{}
+	readResponse(recorder.Result(), &doc)
+	if doc.OpenAPI == "" || len(doc.Paths) == 0 {
+		t.Fatal(recorder.Body.String())
+	}
+
+	create := doc.Paths["/api/v1/game/create"]["post"]
+	ref := create.RequestBody.Content["application/json"].Schema.Ref
+	if ref != "#/components/schemas/CreateGameRequest" || doc.Components.Schemas["CreateGameRequest"].Properties["starterChips"].Type != "integer" {
+		t.Error(ref)
+	}
+	if enum := doc.Components.Schemas["GameType"].Properties["variant"].Enum; len(enum) != 6 || enum[0] != "holdem" {
+		t.Error(enum)
+	}
+	if _, ok := doc.Paths[v2GamesPath+"/{gameID}/players"]["post"].Responses["201"]; !ok {
+		t.Error("Adding a v2 player is created")
+	}
+
+	recorder = httptest.NewRecorder()
+	OpenAPI(recorder, httptest.NewRequest("POST", OpenAPIPath, nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Error(recorder.Code)
+	}
+}
+
+func TestOpenAPIRequests(t *testing.T) {
+	// Every field of a request has to be in the spec, and nothing else can be
+	for _, route := range routes {
+		if route.request == nil {
+			continue
+		}
+
+		body, err := json.Marshal(route.request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var value interface{}
+		json.Unmarshal(body, &value)
+
+		op := openAPI.Paths[route.path][strings.ToLower(route.method)]
+		if err := checkSchema(op.RequestBody.Content["application/json"].Schema, value, route.id); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestOpenAPIResponses(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	recorder := serveTestRequest(gameManager.CreateGame, gameReq)
+	checkResponse(t, "createGame", recorder)
+	gameResponse := GetGameResponse{}
+	readResponse(recorder.Result(), &gameResponse)
+
+	secrets := make([]int, 0)
+	for seat := 0; seat < 2; seat++ {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		recorder = serveTestRequest(gameManager.AddPlayer, playerReq)
+		checkResponse(t, "addPlayer", recorder)
+		playerResponse := AddPlayerResponse{}
+		readResponse(recorder.Result(), &playerResponse)
+		secrets = append(secrets, playerResponse.Secret)
+	}
+
+	handReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 0, Secret: secrets[0]}
+	recorder = serveTestRequest(gameManager.StartHand, handReq)
+	checkResponse(t, "startHand", recorder)
+	hand := poker.HandView{}
+	readResponse(recorder.Result(), &hand)
+
+	actReq := ActRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: hand.ToAct, Secret: secrets[hand.ToAct],
+		Action: poker.Action{Type: poker.Fold}}
+	checkResponse(t, "act", serveTestRequest(gameManager.Act, actReq))
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	checkResponse(t, "getGame", serveTestRequest(gameManager.Game, getReq))
+	checkResponse(t, "ledger", serveTestRequest(gameManager.Ledger, getReq))
+	checkResponse(t, "leaveTable", serveTestRequest(gameManager.LeaveTable, handReq))
+
+	getReq.Passphrase = "wrong"
+	checkResponse(t, "getGame", serveTestRequest(gameManager.Game, getReq))
+
+	headers := map[string]string{passphraseHeader: gameReq.Passphrase}
+	path := fmt.Sprintf("%s/%s", v2GamesPath, gameResponse.GameID)
+	checkResponse(t, "getGameV2", serveV2Request(&gameManager, "GET", path, nil, headers))
+
+	createReq := createTournamentRequest{
+		Passphrase:   "foobar",
+		StarterChips: 1000,
+		Levels:       []poker.BlindLevel{{Blinds: poker.Blinds{SmallBlind: 5, BigBlind: 10}}},
+	}
+	recorder = serveTestRequest(gameManager.CreateTournament, createReq)
+	checkResponse(t, "createTournament", recorder)
+	tournamentResponse := getTournamentResponse{}
+	readResponse(recorder.Result(), &tournamentResponse)
+
+	registerReq := registerRequest{TournamentID: tournamentResponse.TournamentID, Passphrase: createReq.Passphrase, Name: "foo"}
+	recorder = serveTestRequest(gameManager.Register, registerReq)
+	checkResponse(t, "register", recorder)
+	registerResp := registerResponse{}
+	readResponse(recorder.Result(), &registerResp)
+	registerReq.Name = "bar"
+	serveTestRequest(gameManager.Register, registerReq)
+
+	startReq := getTournamentRequest{TournamentID: tournamentResponse.TournamentID, Passphrase: createReq.Passphrase}
+	checkResponse(t, "startTournament", serveTestRequest(gameManager.StartTournament, startReq))
+
+	entrantReq := entrantRequest{TournamentID: tournamentResponse.TournamentID, Passphrase: createReq.Passphrase,
+		Entrant: registerResp.Entrant.ID, Secret: registerResp.Secret}
+	checkResponse(t, "startTournamentHand", serveTestRequest(gameManager.StartTournamentHand, entrantReq))
+
+	checkResponse(t, "payouts", serveTestRequest(Payouts, payoutsRequest{Entrants: 10, PrizePool: 1000}))
+	checkResponse(t, "icm", serveTestRequest(ICM, icmRequest{Stacks: []int{100, 50}, Prizes: []int{70, 30}}))
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+type payoutsRequest struct {
+	Entrants  int `json:"entrants"`
+	PrizePool int `json:"prizePool"`
+	// Percentages are the custom payouts, or left out for the standard ones for the field size
+	Percentages []float64 `json:"percentages"`
+}
+
+type payoutsResponse struct {
+	Percentages []float64 `json:"percentages"`
+	Prizes      []int     `json:"prizes"`
+}
+
+// Payouts splits up a prize pool by finishing place
+func Payouts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	request := payoutsRequest{}
+	if ok := decodeJSONBody(w, r, &request); !ok {
+		return
+	}
+
+	payouts := poker.PayoutStructure{Percentages: request.Percentages}
+	if len(payouts.Percentages) == 0 {
+		payouts = poker.StandardPayouts(request.Entrants)
+	}
+
+	prizes, err := payouts.Prizes(request.PrizePool)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	sendJSONResponse(w, r, payoutsResponse{Percentages: payouts.Percentages, Prizes: prizes})
+}
+
+type icmRequest struct {
+	Stacks []int `json:"stacks"`
+	Prizes []int `json:"prizes"`
+}
+
+type icmResponse struct {
+	Equity []float64 `json:"equity"`
+}
+
+// ICM works out what each stack is worth in prize money, so a final table can compare it to
+// a proposed deal
+func ICM(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	request := icmRequest{}
+	if ok := decodeJSONBody(w, r, &request); !ok {
+		return
+	}
+
+	equity, err := poker.ICM(request.Stacks, request.Prizes)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	sendJSONResponse(w, r, icmResponse{Equity: equity})
+}
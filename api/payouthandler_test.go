@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPayoutsApi(t *testing.T) {
+	response := payoutsResponse{}
+	readResponse(serveTestRequest(Payouts, payoutsRequest{Entrants: 9, PrizePool: 900}).Result(), &response)
+	if len(response.Prizes) != 3 || response.Prizes[0] != 450 {
+		t.Error(response)
+	}
+
+	recorder := serveTestRequest(Payouts, payoutsRequest{PrizePool: 900, Percentages: []float64{60, 30}})
+	if recorder.Code != http.StatusBadRequest {
+		t.Error("Percentages need to add up to 100")
+	}
+}
+
+func TestICMApi(t *testing.T) {
+	response := icmResponse{}
+	readResponse(serveTestRequest(ICM, icmRequest{Stacks: []int{1000, 1000}, Prizes: []int{60, 40}}).Result(), &response)
+	if len(response.Equity) != 2 || response.Equity[0] != 50 {
+		t.Error(response)
+	}
+
+	if recorder := serveTestRequest(ICM, icmRequest{Stacks: []int{}, Prizes: []int{60}}); recorder.Code != http.StatusBadRequest {
+		t.Error()
+	}
+}
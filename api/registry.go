@@ -0,0 +1,181 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// gameCodeLength is how many characters are in a game's id. Each one is 5 random bits, so
+// there are 2^40 ids to guess from
+const gameCodeLength = 8
+
+// gameCodeEncoding is base32 with letters and numbers that are easy to tell apart, since ids
+// get read out and typed in by people
+var gameCodeEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// newGameCode makes a random id for a game
+func newGameCode() (string, error) {
+	random := make([]byte, gameCodeLength*5/8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return gameCodeEncoding.EncodeToString(random), nil
+}
+
+// newToken makes a random token which is hard to guess, for things like hosting or spectating
+func newToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// addGame gives game a new random id and host token, and starts its loop. The loop owns game
+// from then on, so the caller can't touch it again. Private games are left out of the lobby
+func (manager *GameManager) addGame(game *poker.Game, passphrase string, private bool) (*gameLoop, error) {
+	hostToken, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	manager.gamesLock.Lock()
+	defer manager.gamesLock.Unlock()
+
+	// Codes are random, so one which is taken is unlikely but not impossible
+	var gameID string
+	for gameID == "" || manager.games[gameID] != nil {
+		code, err := newGameCode()
+		if err != nil {
+			return nil, err
+		}
+		gameID = code
+	}
+	loop := manager.startGameLoop(gameID, game, passphrase, hostToken, private)
+	manager.games[gameID] = loop
+	return loop, nil
+}
+
+// findGame gets the loop running a game. An invite to the game works in place of its
+// passphrase, so people who were invited never need to be told it. A game which doesn't exist
+// and a wrong passphrase get the same error, so ids can't be guessed one at a time
+func (manager *GameManager) findGame(gameID string, passphrase string) (*gameLoop, error) {
+	loop, err := manager.lookupGame(gameID)
+	if err != nil || (passphrase != loop.passphrase && !manager.invitedTo(gameID, passphrase)) {
+		return nil, newRequestError(CodeGameNotFound, "gameID", "Could not find game %s with that passphrase", gameID)
+	}
+
+	return loop, nil
+}
+
+// lookupGame gets the loop running a game without checking the passphrase, for requests which
+// have their own way of getting in
+func (manager *GameManager) lookupGame(gameID string) (*gameLoop, error) {
+	manager.gamesLock.RLock()
+	loop, ok := manager.games[gameID]
+	manager.gamesLock.RUnlock()
+
+	if !ok {
+		return nil, newRequestError(CodeGameNotFound, "gameID", "Could not find game %s", gameID)
+	}
+	return loop, nil
+}
+
+// findGameV2 finds the game with the passphrase from the request's headers. A game which
+// doesn't exist and a wrong passphrase both look like the game isn't there
+func (manager *GameManager) findGameV2(w http.ResponseWriter, r *http.Request, gameID string) (*gameLoop, bool) {
+	loop, err := manager.findGame(gameID, r.Header.Get(passphraseHeader))
+	if err != nil {
+		sendError(w, r, http.StatusNotFound, err)
+		return nil, false
+	}
+	return loop, true
+}
+
+// CloseGame stops a game's loop and takes it out of the registry. Requests already waiting on
+// the game are told it has been closed
+func (manager *GameManager) CloseGame(gameID string) bool {
+	manager.gamesLock.Lock()
+	loop, ok := manager.games[gameID]
+	delete(manager.games, gameID)
+	manager.gamesLock.Unlock()
+
+	if ok {
+		loop.close()
+	}
+	return ok
+}
+
+// removeGame takes loop out of the registry without waiting for it to stop, so the loop can
+// remove itself
+func (manager *GameManager) removeGame(loop *gameLoop) {
+	manager.gamesLock.Lock()
+	defer manager.gamesLock.Unlock()
+	if manager.games[loop.gameID] == loop {
+		delete(manager.games, loop.gameID)
+	}
+}
+
+// Close stops every game's and tournament's loop, for shutting down the server
+func (manager *GameManager) Close() {
+	manager.gamesLock.Lock()
+	loops := manager.games
+	manager.games = make(map[string]*gameLoop)
+	manager.gamesLock.Unlock()
+
+	for _, loop := range loops {
+		loop.close()
+	}
+
+	manager.tournamentsLock.Lock()
+	tournamentLoops := manager.tournaments
+	manager.tournaments = make(map[int]*tournamentLoop)
+	manager.tournamentsLock.Unlock()
+
+	for _, loop := range tournamentLoops {
+		loop.close()
+	}
+}
+
+// addTournament gives tournament the next id, and starts its loop. The loop owns tournament from
+// then on, so the caller can't touch it again
+func (manager *GameManager) addTournament(tournament *poker.MultiTableTournament, passphrase string) *tournamentLoop {
+	manager.tournamentsLock.Lock()
+	defer manager.tournamentsLock.Unlock()
+
+	tournamentID := manager.tournamentCounter
+	manager.tournamentCounter++
+	loop := manager.startTournamentLoop(tournamentID, tournament, passphrase)
+	manager.tournaments[tournamentID] = loop
+	return loop
+}
+
+// findTournament gets the loop running a tournament
+func (manager *GameManager) findTournament(tournamentID int, passphrase string) (*tournamentLoop, error) {
+	manager.tournamentsLock.RLock()
+	loop, ok := manager.tournaments[tournamentID]
+	manager.tournamentsLock.RUnlock()
+
+	if !ok {
+		return nil, newRequestError(CodeTournamentNotFound, "tournamentID", "Could not find tournament %d", tournamentID)
+	}
+	if passphrase != loop.passphrase {
+		return nil, newRequestError(CodeWrongPassphrase, "passphrase", "Wrong passphrase for tournament %d", tournamentID)
+	}
+
+	return loop, nil
+}
+
+// removeTournament takes loop out of the registry without waiting for it to stop, so the loop
+// can remove itself
+func (manager *GameManager) removeTournament(loop *tournamentLoop) {
+	manager.tournamentsLock.Lock()
+	defer manager.tournamentsLock.Unlock()
+	if manager.tournaments[loop.tournamentID] == loop {
+		delete(manager.tournaments, loop.tournamentID)
+	}
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func TestConcurrentGames(t *testing.T) {
+	gameManager := NewGameManager()
+	const numGames = 24
+
+	var wg sync.WaitGroup
+	errs := make(chan error, numGames)
+	for i := 0; i < numGames; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- playConcurrentGame(&gameManager, fmt.Sprint("game", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if len(gameManager.games) != numGames {
+		t.Error(len(gameManager.games))
+	}
+}
+
+// playConcurrentGame plays a few hands of a game while other requests read it at the same time
+func playConcurrentGame(gameManager *GameManager, passphrase string) error {
+	gameReq := CreateGameRequest{Passphrase: passphrase, StarterChips: 100, BlindSize: 10}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	secrets := make([]int, 0)
+	for seat := 0; seat < 3; seat++ {
+		playerReq := AddPlayerRequest{Name: fmt.Sprint("player", seat), Seat: seat, Passphrase: passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		secrets = append(secrets, playerResponse.Secret)
+	}
+
+	done := make(chan bool)
+	go func() {
+		getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: passphrase}
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				serveTestRequest(gameManager.Game, getReq)
+				serveTestRequest(gameManager.Ledger, getReq)
+			}
+		}
+	}()
+	defer close(done)
+
+	for i := 0; i < 5; i++ {
+		handReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: passphrase, Seat: 0, Secret: secrets[0]}
+		recorder := serveTestRequest(gameManager.StartHand, handReq)
+		if recorder.Code != http.StatusOK {
+			return fmt.Errorf("%s: %s", passphrase, recorder.Body.String())
+		}
+		hand := poker.HandView{}
+		readResponse(recorder.Result(), &hand)
+
+		for !hand.Over {
+			actReq := ActRequest{GameID: gameResponse.GameID, Passphrase: passphrase, Seat: hand.ToAct, Secret: secrets[hand.ToAct],
+				Action: poker.Action{Type: poker.Fold}}
+			recorder := serveTestRequest(gameManager.Act, actReq)
+			if recorder.Code != http.StatusOK {
+				return fmt.Errorf("%s: %s", passphrase, recorder.Body.String())
+			}
+			readResponse(recorder.Result(), &hand)
+		}
+		if hand.Number != i+1 {
+			return fmt.Errorf("%s: dealt hand %d, not %d", passphrase, hand.Number, i+1)
+		}
+	}
+	return nil
+}
+
+func TestConcurrentTournaments(t *testing.T) {
+	gameManager := NewGameManager()
+	createReq := createTournamentRequest{
+		Passphrase:   "foobar",
+		StarterChips: 1000,
+		Levels:       []poker.BlindLevel{{Blinds: poker.Blinds{SmallBlind: 5, BigBlind: 10}}},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tournamentResponse := getTournamentResponse{}
+			readResponse(serveTestRequest(gameManager.CreateTournament, createReq).Result(), &tournamentResponse)
+			for j := 0; j < 10; j++ {
+				registerReq := registerRequest{TournamentID: tournamentResponse.TournamentID, Passphrase: createReq.Passphrase, Name: "foo"}
+				serveTestRequest(gameManager.Register, registerReq)
+			}
+			startReq := getTournamentRequest{TournamentID: tournamentResponse.TournamentID, Passphrase: createReq.Passphrase}
+			serveTestRequest(gameManager.StartTournament, startReq)
+		}()
+	}
+	wg.Wait()
+
+	for id, loop := range gameManager.tournaments {
+		loop.run(func(tournament *poker.MultiTableTournament) {
+			if !tournament.Started() || len(tournament.Entrants()) != 10 {
+				t.Error("Tournament", id, "should have started with ten entrants")
+			}
+		})
+	}
+}
+
+func TestGamesDontWaitOnEachOther(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	ids := make([]string, 0)
+	for i := 0; i < 2; i++ {
+		gameResponse := GetGameResponse{}
+		readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+		ids = append(ids, gameResponse.GameID)
+	}
+
+	// Game 0's loop is kept busy until the test is over
+	loop, err := gameManager.findGame(ids[0], gameReq.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy, release := make(chan bool), make(chan bool)
+	go loop.run(func(game *poker.Game) {
+		close(busy)
+		<-release
+	})
+	<-busy
+	defer close(release)
+
+	served := make(chan int)
+	go func() {
+		getReq := GetGameRequest{GameID: ids[1], Passphrase: gameReq.Passphrase}
+		served <- serveTestRequest(gameManager.Game, getReq).Code
+	}()
+	select {
+	case code := <-served:
+		if code != http.StatusOK {
+			t.Error(code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Game 1 waited on game 0")
+	}
+}
+
+func TestGameCodes(t *testing.T) {
+	codes := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		code, err := newGameCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != gameCodeLength || strings.Trim(code, "abcdefghijkmnpqrstuvwxyz23456789") != "" {
+			t.Fatal(code)
+		}
+		if codes[code] {
+			t.Fatal("Game codes should be random", code)
+		}
+		codes[code] = true
+	}
+}
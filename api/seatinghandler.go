@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// SitOutRequest sits a player out, or brings them back in
+type SitOutRequest struct {
+	GameID     string `json:"gameID"`
+	Passphrase string `json:"passphrase"`
+	Seat       int    `json:"seat"`
+	Secret     int    `json:"secret"`
+	// Cancel sits the player back in
+	Cancel bool `json:"cancel"`
+}
+
+// SitOut has a player skip hands while keeping their seat, or come back
+func (manager *GameManager) SitOut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	sitOut := SitOutRequest{}
+	if ok := decodeJSONBody(w, r, &sitOut); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(sitOut.GameID, sitOut.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, sitOut.Seat, sitOut.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		if sitOut.Cancel {
+			err = game.SitIn(sitOut.Seat)
+		} else {
+			err = game.SitOut(sitOut.Seat)
+		}
+		if err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		sendJSONResponse(w, r, newGetGameResponse(sitOut.GameID, game))
+	})
+}
+
+// LeaveTable cashes out a player and frees up their seat, responding with their ledger entry
+func (manager *GameManager) LeaveTable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	leave := PlayerRequest{}
+	if ok := decodeJSONBody(w, r, &leave); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(leave.GameID, leave.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := resolvePlayer(game, leave.Seat, leave.Secret); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		ledgerEntry, err := game.LeaveTable(leave.Seat)
+		if err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		manager.offerSeats(leave.GameID, game)
+
+		sendJSONResponse(w, r, ledgerEntry)
+	})
+}
+
+type reserveSeatRequest struct {
+	GameID     string `json:"gameID"`
+	Passphrase string `json:"passphrase"`
+	Seat       int    `json:"seat"`
+	Name       string `json:"name"`
+	Minutes    int    `json:"minutes"`
+	// Cancel frees up the seat, which takes the claim the reservation was made with
+	Cancel bool `json:"cancel"`
+	Claim  int  `json:"claim"`
+}
+
+// reserveSeatResponse is the game with the seat held, and the claim for sitting in it. The claim
+// is only ever given out here, so it should go to whoever the seat is for
+type reserveSeatResponse struct {
+	GetGameResponse
+	Claim int `json:"claim,omitempty"`
+}
+
+// ReserveSeat holds an empty seat for a player for some minutes, or frees it up
+func (manager *GameManager) ReserveSeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	reserve := reserveSeatRequest{}
+	if ok := decodeJSONBody(w, r, &reserve); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(reserve.GameID, reserve.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		reservation := poker.Reservation{}
+		if reserve.Cancel {
+			err = game.CancelReservation(reserve.Seat, reserve.Claim)
+		} else {
+			reservation, err = game.ReserveSeat(reserve.Seat, reserve.Name, time.Duration(reserve.Minutes)*time.Minute)
+		}
+		if err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		manager.offerSeats(reserve.GameID, game)
+
+		sendJSONResponse(w, r, reserveSeatResponse{GetGameResponse: newGetGameResponse(reserve.GameID, game), Claim: reservation.Claim()})
+	})
+}
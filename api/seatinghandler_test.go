@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func TestSeatingApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	reserveReq := reserveSeatRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 2, Name: "bar", Minutes: 5}
+	reserveResponse := reserveSeatResponse{}
+	readResponse(serveTestRequest(gameManager.ReserveSeat, reserveReq).Result(), &reserveResponse)
+	if reserveResponse.Reservations[2].Name != "bar" || len(reserveResponse.EmptySeats) != poker.DefaultTableSize-1 {
+		t.Error("Seat 2 should be held for bar")
+	}
+	if reserveResponse.Claim == 0 {
+		t.Fatal("Reserving gives the claim for the seat")
+	}
+
+	cancelReq := reserveSeatRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 2, Cancel: true}
+	if recorder := serveTestRequest(gameManager.ReserveSeat, cancelReq); recorder.Code != http.StatusForbidden {
+		t.Error("Only whoever has the claim can cancel the reservation", recorder.Code)
+	}
+
+	playerReq := AddPlayerRequest{Name: "bar", Seat: 2, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+	if recorder := serveTestRequest(gameManager.AddPlayer, playerReq); recorder.Code != http.StatusConflict {
+		t.Error("Seat is reserved")
+	}
+	playerReq.Claim = reserveResponse.Claim
+	playerResponse := AddPlayerResponse{}
+	readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+
+	sitOutReq := SitOutRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 2, Secret: playerResponse.Secret}
+	readResponse(serveTestRequest(gameManager.SitOut, sitOutReq).Result(), &gameResponse)
+	if !gameResponse.Players[2].SittingOut {
+		t.Error()
+	}
+
+	leaveReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 2, Secret: playerResponse.Secret}
+	entry := poker.LedgerEntry{}
+	readResponse(serveTestRequest(gameManager.LeaveTable, leaveReq).Result(), &entry)
+	if !entry.Left || entry.CashOut != 100 {
+		t.Error(entry)
+	}
+
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase}
+	statusResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.Game, getReq).Result(), &statusResponse)
+	if len(statusResponse.Players) != 0 || len(statusResponse.EmptySeats) != poker.DefaultTableSize {
+		t.Error("Seat should be free after leaving")
+	}
+}
+
+func TestTableSizeApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, TableSize: 11}
+	if recorder := serveTestRequest(gameManager.CreateGame, gameReq); recorder.Code != http.StatusBadRequest {
+		t.Error("Table is too big")
+	}
+
+	gameReq.TableSize = 6
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+	if gameResponse.TableSize != 6 || len(gameResponse.EmptySeats) != 6 {
+		t.Error(gameResponse.TableSize, gameResponse.EmptySeats)
+	}
+
+	playerReq := AddPlayerRequest{Name: "foo", Seat: 6, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+	if recorder := serveTestRequest(gameManager.AddPlayer, playerReq); recorder.Code != http.StatusBadRequest {
+		t.Error("6-max table has no seat 6")
+	}
+}
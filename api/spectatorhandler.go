@@ -0,0 +1,148 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// SpectateRequest starts watching a game without a seat, with an invite to spectate it. The
+// passphrase isn't enough, since it would show spectators everything the table sees
+type SpectateRequest struct {
+	Invite string `json:"invite"`
+}
+
+// SpectateResponse has the token the spectator watches the game with, and the game as they see it
+type SpectateResponse struct {
+	Token string          `json:"token"`
+	Game  GetGameResponse `json:"game"`
+}
+
+// WatchRequest gets the game as a spectator sees it. The token is all a spectator needs, so it
+// can be shared without the passphrase
+type WatchRequest struct {
+	GameID string `json:"gameID"`
+	Token  string `json:"token"`
+}
+
+type spectatorRulesRequest struct {
+	GameID    string               `json:"gameID"`
+	HostToken string               `json:"hostToken"`
+	Rules     poker.SpectatorRules `json:"rules"`
+}
+
+// newSpectatorResponse is the state of a game as someone without a seat sees it, which holds
+// back showdowns until the game's delay has passed
+func newSpectatorResponse(gameID string, game *poker.Game) GetGameResponse {
+	response := newGetGameResponse(gameID, game)
+	response.Players = game.SpectatorPlayers()
+	response.Hand = nil
+	if hand, ok := game.SpectatorView(); ok {
+		response.Hand = &hand
+	}
+	return response
+}
+
+// Spectate lets someone with an invite to spectate watch a game without sitting down, unless
+// the game has turned spectating off
+func (manager *GameManager) Spectate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	spectate := SpectateRequest{}
+	if ok := decodeJSONBody(w, r, &spectate); !ok {
+		return
+	}
+
+	inv, err := manager.readInvite(spectate.Invite)
+	if err == nil && !inv.Spectate {
+		err = newRequestError(CodeInvalidInvite, "invite", "Invite is to sit at the game, not spectate it")
+	}
+	if err != nil {
+		sendError(w, r, http.StatusForbidden, err)
+		return
+	}
+
+	loop, err := manager.lookupGame(inv.GameID)
+	if err != nil {
+		sendError(w, r, http.StatusNotFound, newRequestError(CodeGameNotFound, "invite", "Game %s is over", inv.GameID))
+		return
+	}
+	token, err := newToken()
+	if err != nil {
+		sendError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := game.AddSpectator(token); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		sendJSONResponse(w, r, SpectateResponse{Token: token, Game: newSpectatorResponse(loop.gameID, game)})
+	})
+}
+
+// Watch gets the game as a spectator sees it, which never has anyone's hole cards. Spectators
+// need to keep watching to stay counted, or they have to spectate again
+func (manager *GameManager) Watch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	watch := WatchRequest{}
+	if ok := decodeJSONBody(w, r, &watch); !ok {
+		return
+	}
+
+	loop, err := manager.lookupGame(watch.GameID)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := game.Watch(watch.Token); err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		manager.updateGame(watch.GameID, game)
+		sendJSONResponse(w, r, newSpectatorResponse(watch.GameID, game))
+	})
+}
+
+// SpectatorRules has the host turn spectating on or off, or change how long spectators wait
+// to see showdowns. Turning it off sends away everyone watching. Only the host token from
+// creating the game can change them, since everyone at the table has the passphrase
+func (manager *GameManager) SpectatorRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	rules := spectatorRulesRequest{}
+	if ok := decodeJSONBody(w, r, &rules); !ok {
+		return
+	}
+
+	loop, err := manager.lookupGame(rules.GameID)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if rules.HostToken != loop.hostToken {
+		sendError(w, r, http.StatusForbidden, newRequestError(CodeNotHost, "hostToken", "Only the host can change the spectator rules"))
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if err := game.SetSpectatorRules(rules.Rules); err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		sendJSONResponse(w, r, newGetGameResponse(rules.GameID, game))
+	})
+}
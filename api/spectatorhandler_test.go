@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func TestSpectatorApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, Spectators: poker.SpectatorRules{ShowdownDelay: time.Hour}}
+	created := CreateGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &created)
+	gameResponse := created.GetGameResponse
+	if created.HostToken == "" {
+		t.Fatal("The host gets a token")
+	}
+
+	secrets := make([]int, 0)
+	for seat := 0; seat < 2; seat++ {
+		playerReq := AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: "foobar", GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		secrets = append(secrets, playerResponse.Secret)
+	}
+
+	inviteReq := InviteRequest{GameID: gameResponse.GameID, Passphrase: "foobar"}
+	seatInvite := InviteResponse{}
+	readResponse(serveTestRequest(gameManager.Invite, inviteReq).Result(), &seatInvite)
+	inviteReq.Spectate = true
+	spectatorInvite := InviteResponse{}
+	readResponse(serveTestRequest(gameManager.Invite, inviteReq).Result(), &spectatorInvite)
+
+	// Spectators only get in with an invite to spectate, which can't see what the table sees
+	for _, token := range []string{"foobar", seatInvite.Invite} {
+		if recorder := serveTestRequest(gameManager.Spectate, SpectateRequest{Invite: token}); recorder.Code != http.StatusForbidden {
+			t.Error("Spectating needs an invite to spectate", recorder.Code)
+		}
+	}
+	getReq := GetGameRequest{GameID: gameResponse.GameID, Passphrase: spectatorInvite.Invite}
+	if recorder := serveTestRequest(gameManager.Game, getReq); recorder.Code != http.StatusBadRequest {
+		t.Error("An invite to spectate isn't the passphrase", recorder.Code)
+	}
+	if recorder := serveTestRequest(gameManager.Join, JoinRequest{Invite: spectatorInvite.Invite}); recorder.Code != http.StatusForbidden {
+		t.Error("An invite to spectate can't join the table", recorder.Code)
+	}
+	recorder := serveTestRequest(gameManager.Spectate, SpectateRequest{Invite: spectatorInvite.Invite})
+	checkResponse(t, "spectate", recorder)
+	spectate := SpectateResponse{}
+	readResponse(recorder.Result(), &spectate)
+	if spectate.Token == "" || spectate.Game.Spectators != 1 {
+		t.Fatal(spectate)
+	}
+
+	handReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: "foobar", Seat: 0, Secret: secrets[0]}
+	hand := poker.HandView{}
+	readResponse(serveTestRequest(gameManager.StartHand, handReq).Result(), &hand)
+	watchReq := WatchRequest{GameID: gameResponse.GameID, Token: spectate.Token}
+	recorder = serveTestRequest(gameManager.Watch, watchReq)
+	checkResponse(t, "watch", recorder)
+	readResponse(recorder.Result(), &gameResponse)
+	if gameResponse.Hand == nil || len(gameResponse.Hand.Seats[0].HoleCards) != 0 {
+		t.Fatal("Spectators can't see hole cards", gameResponse.Hand)
+	}
+
+	for !hand.Over {
+		seat := hand.ToAct
+		playerReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: "foobar", Seat: seat, Secret: secrets[seat]}
+		readResponse(serveTestRequest(gameManager.Hand, playerReq).Result(), &hand)
+		action := poker.Action{Type: poker.Check}
+		if hand.Options.ToCall > 0 {
+			action.Type = poker.Call
+		}
+		actReq := ActRequest{GameID: gameResponse.GameID, Passphrase: "foobar", Seat: seat, Secret: secrets[seat], Action: action}
+		readResponse(serveTestRequest(gameManager.Act, actReq).Result(), &hand)
+	}
+
+	getReq.Passphrase = "foobar"
+	readResponse(serveTestRequest(gameManager.Game, getReq).Result(), &gameResponse)
+	if len(gameResponse.Hand.Seats[0].HoleCards) != 2 {
+		t.Fatal("The table sees the showdown")
+	}
+	gameResponse = GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.Watch, watchReq).Result(), &gameResponse)
+	if !gameResponse.Hand.Over || len(gameResponse.Hand.Seats[0].HoleCards) != 0 || len(gameResponse.Hand.Results) != 0 {
+		t.Error("Spectators wait to see the showdown", gameResponse.Hand)
+	}
+	if gameResponse.Players[0].Chips != 90 || gameResponse.Players[1].Chips != 90 {
+		t.Error("Spectators wait to see who won", gameResponse.Players)
+	}
+
+	badWatch := WatchRequest{GameID: gameResponse.GameID, Token: "guess"}
+	if recorder := serveTestRequest(gameManager.Watch, badWatch); recorder.Code != http.StatusForbidden {
+		t.Error("Watching needs a token", recorder.Code)
+	}
+
+	// Players have the passphrase, but only the host can turn spectating off, which sends the
+	// spectator away
+	rulesReq := spectatorRulesRequest{GameID: gameResponse.GameID, HostToken: "foobar", Rules: poker.SpectatorRules{Disabled: true}}
+	if recorder := serveTestRequest(gameManager.SpectatorRules, rulesReq); recorder.Code != http.StatusForbidden {
+		t.Error("Only the host changes the spectator rules", recorder.Code)
+	}
+	rulesReq.HostToken = created.HostToken
+	recorder = serveTestRequest(gameManager.SpectatorRules, rulesReq)
+	checkResponse(t, "spectatorRules", recorder)
+	readResponse(recorder.Result(), &gameResponse)
+	if !gameResponse.SpectatorRules.Disabled || gameResponse.Spectators != 0 {
+		t.Error(gameResponse.SpectatorRules, gameResponse.Spectators)
+	}
+	checks := []struct {
+		handler http.HandlerFunc
+		body    interface{}
+		code    ErrorCode
+	}{
+		{gameManager.Watch, watchReq, CodeSpectatorNotFound},
+		{gameManager.Spectate, SpectateRequest{Invite: spectatorInvite.Invite}, CodeSpectatingDisabled},
+	}
+	for _, check := range checks {
+		recorder := serveTestRequest(check.handler, check.body)
+		response := ErrorResponse{}
+		readResponse(recorder.Result(), &response)
+		if recorder.Code != http.StatusForbidden || response.Code != check.code {
+			t.Error(recorder.Code, response)
+		}
+	}
+}
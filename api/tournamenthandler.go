@@ -0,0 +1,309 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+type createTournamentRequest struct {
+	Passphrase       string                 `json:"passphrase"`
+	StarterChips     int                    `json:"starterChips"`
+	TableSize        int                    `json:"tableSize"`
+	Levels           []poker.BlindLevel     `json:"levels"`
+	BettingStructure poker.BettingStructure `json:"bettingStructure"`
+	Rotation         poker.Rotation         `json:"rotation"`
+	BuyIn            int                    `json:"buyIn"`
+	Payouts          poker.PayoutStructure  `json:"payouts"`
+	Rebuys           poker.RebuyRules       `json:"rebuys"`
+	ShotClock        poker.ShotClock        `json:"shotClock"`
+	RebuyLevels      int                    `json:"rebuyLevels"`
+	MaxReEntries     int                    `json:"maxReEntries"`
+	AddOn            poker.AddOn            `json:"addOn"`
+}
+
+type getTournamentRequest struct {
+	TournamentID int    `json:"tournamentID"`
+	Passphrase   string `json:"passphrase"`
+}
+
+type tableResponse struct {
+	TableSize int                  `json:"tableSize"`
+	Players   map[int]poker.Player `json:"players"`
+	Button    int                  `json:"button"`
+	Hand      *poker.HandView      `json:"hand"`
+}
+
+type getTournamentResponse struct {
+	TournamentID int                   `json:"tournamentID"`
+	Started      bool                  `json:"started"`
+	Over         bool                  `json:"over"`
+	Levels       []poker.BlindLevel    `json:"levels"`
+	CurrentLevel int                   `json:"currentLevel"`
+	OnBreak      bool                  `json:"onBreak"`
+	BreakEnds    *time.Time            `json:"breakEnds"`
+	Entrants     []poker.Entrant       `json:"entrants"`
+	Tables       map[int]tableResponse `json:"tables"`
+	Moves        []poker.TableMove     `json:"moves"`
+	Standings    []poker.Finish        `json:"standings"`
+	Prizes       []int                 `json:"prizes"`
+}
+
+func newGetTournamentResponse(tournamentID int, tournament *poker.MultiTableTournament) getTournamentResponse {
+	response := getTournamentResponse{
+		TournamentID: tournamentID,
+		Started:      tournament.Started(),
+		Over:         tournament.Over(),
+		Levels:       tournament.Levels(),
+		CurrentLevel: tournament.CurrentLevel(),
+		Entrants:     tournament.Entrants(),
+		Tables:       make(map[int]tableResponse),
+		Moves:        tournament.Moves(),
+		Standings:    tournament.Standings(),
+	}
+
+	if prizes, err := tournament.Prizes(); err == nil {
+		response.Prizes = prizes
+	}
+	if onBreak, ends := tournament.OnBreak(); onBreak {
+		response.OnBreak = true
+		response.BreakEnds = &ends
+	}
+	for _, table := range tournament.Tables() {
+		game, _ := tournament.Table(table)
+		tableView := tableResponse{TableSize: game.TableSize(), Players: game.Players(), Button: game.Button()}
+		if hand, ok := game.HandView(-1); ok {
+			tableView.Hand = &hand
+		}
+		response.Tables[table] = tableView
+	}
+
+	return response
+}
+
+// Tournament is a restful endpoint for getting a multi-table tournament
+func (manager *GameManager) Tournament(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	get := getTournamentRequest{}
+	if ok := decodeJSONBody(w, r, &get); !ok {
+		return
+	}
+
+	loop, err := manager.findTournament(get.TournamentID, get.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(tournament *poker.MultiTableTournament) {
+		updateTournament(tournament)
+
+		sendJSONResponse(w, r, newGetTournamentResponse(get.TournamentID, tournament))
+	})
+}
+
+// CreateTournament creates a multi-table tournament in the GameManager
+func (manager *GameManager) CreateTournament(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	create := createTournamentRequest{}
+	if ok := decodeJSONBody(w, r, &create); !ok {
+		return
+	}
+
+	tournament, err := poker.NewMultiTableTournament(poker.TournamentOptions{
+		Game: poker.GameOptions{
+			StarterChips: create.StarterChips,
+			TableSize:    create.TableSize,
+			Betting:      create.BettingStructure,
+			Rotation:     create.Rotation,
+			Rebuys:       create.Rebuys,
+			ShotClock:    create.ShotClock,
+			Clock:        manager.clock,
+		},
+		Levels:       create.Levels,
+		BuyIn:        create.BuyIn,
+		Payouts:      create.Payouts,
+		RebuyLevels:  create.RebuyLevels,
+		MaxReEntries: create.MaxReEntries,
+		AddOn:        create.AddOn,
+	})
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	loop := manager.addTournament(&tournament, create.Passphrase)
+	loop.do(w, r, func(tournament *poker.MultiTableTournament) {
+		sendJSONResponse(w, r, newGetTournamentResponse(loop.tournamentID, tournament))
+	})
+}
+
+type registerRequest struct {
+	TournamentID int    `json:"tournamentID"`
+	Passphrase   string `json:"passphrase"`
+	Name         string `json:"name"`
+}
+
+type registerResponse struct {
+	Entrant poker.Entrant `json:"entrant"`
+	Secret  int           `json:"secret"`
+}
+
+// Register signs a player up for a tournament which hasn't started
+func (manager *GameManager) Register(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	register := registerRequest{}
+	if ok := decodeJSONBody(w, r, &register); !ok {
+		return
+	}
+
+	loop, err := manager.findTournament(register.TournamentID, register.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(tournament *poker.MultiTableTournament) {
+		entrant, err := tournament.Register(register.Name)
+		if err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		sendJSONResponse(w, r, registerResponse{Entrant: entrant, Secret: entrant.Secret()})
+	})
+}
+
+// StartTournament seats everyone who has registered
+func (manager *GameManager) StartTournament(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	start := getTournamentRequest{}
+	if ok := decodeJSONBody(w, r, &start); !ok {
+		return
+	}
+
+	loop, err := manager.findTournament(start.TournamentID, start.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(tournament *poker.MultiTableTournament) {
+		if err := tournament.Start(); err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		sendJSONResponse(w, r, newGetTournamentResponse(start.TournamentID, tournament))
+	})
+}
+
+type entrantRequest struct {
+	TournamentID int          `json:"tournamentID"`
+	Passphrase   string       `json:"passphrase"`
+	Entrant      int          `json:"entrant"`
+	Secret       int          `json:"secret"`
+	Action       poker.Action `json:"action"`
+}
+
+// StartTournamentHand deals the next hand at the table the entrant is sitting at, moving
+// players between tables first if they need balancing
+func (manager *GameManager) StartTournamentHand(w http.ResponseWriter, r *http.Request) {
+	manager.handleEntrant(w, r, func(tournament *poker.MultiTableTournament, entrant poker.Entrant, request entrantRequest) error {
+		return tournament.StartHand(entrant.Table)
+	})
+}
+
+// TournamentAct takes an action for the entrant at their table
+func (manager *GameManager) TournamentAct(w http.ResponseWriter, r *http.Request) {
+	manager.handleEntrant(w, r, func(tournament *poker.MultiTableTournament, entrant poker.Entrant, request entrantRequest) error {
+		return tournament.Act(entrant.ID, request.Action)
+	})
+}
+
+// TournamentRebuy buys the entrant more chips
+func (manager *GameManager) TournamentRebuy(w http.ResponseWriter, r *http.Request) {
+	manager.handleEntrant(w, r, func(tournament *poker.MultiTableTournament, entrant poker.Entrant, request entrantRequest) error {
+		return tournament.Rebuy(entrant.ID)
+	})
+}
+
+// TournamentAddOn buys the entrant the add-on
+func (manager *GameManager) TournamentAddOn(w http.ResponseWriter, r *http.Request) {
+	manager.handleEntrant(w, r, func(tournament *poker.MultiTableTournament, entrant poker.Entrant, request entrantRequest) error {
+		return tournament.AddOn(entrant.ID)
+	})
+}
+
+// TournamentReEnter buys a knocked out entrant back in at a new seat
+func (manager *GameManager) TournamentReEnter(w http.ResponseWriter, r *http.Request) {
+	manager.handleEntrant(w, r, func(tournament *poker.MultiTableTournament, entrant poker.Entrant, request entrantRequest) error {
+		return tournament.ReEnter(entrant.ID)
+	})
+}
+
+// handleEntrant does the common work of an entrant's request, and responds with the hand at
+// their table as they see it
+func (manager *GameManager) handleEntrant(w http.ResponseWriter, r *http.Request,
+	handle func(*poker.MultiTableTournament, poker.Entrant, entrantRequest) error) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	request := entrantRequest{}
+	if ok := decodeJSONBody(w, r, &request); !ok {
+		return
+	}
+
+	loop, err := manager.findTournament(request.TournamentID, request.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(tournament *poker.MultiTableTournament) {
+		entrant, err := resolveEntrant(tournament, request.Entrant, request.Secret)
+		if err != nil {
+			sendError(w, r, http.StatusForbidden, err)
+			return
+		}
+
+		if err := handle(tournament, entrant, request); err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// The entrant may have been moved or knocked out by the request
+		entrant, _ = tournament.Entrant(entrant.ID)
+		game, ok := tournament.Table(entrant.Table)
+		if !ok {
+			sendJSONResponse(w, r, newGetTournamentResponse(request.TournamentID, tournament))
+			return
+		}
+		hand, _ := game.HandView(entrant.Seat)
+		sendJSONResponse(w, r, hand)
+	})
+}
+
+func resolveEntrant(tournament *poker.MultiTableTournament, id int, secret int) (poker.Entrant, error) {
+	entrant, ok := tournament.Entrant(id)
+	if !ok || entrant.Secret() != secret {
+		return poker.Entrant{}, newRequestError(CodeEntrantNotFound, "secret", "Could not find entrant")
+	}
+
+	return entrant, nil
+}
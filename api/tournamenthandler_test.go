@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func TestTournamentApi(t *testing.T) {
+	gameManager := NewGameManager()
+	createReq := createTournamentRequest{
+		Passphrase:   "foobar",
+		StarterChips: 1000,
+		Levels:       []poker.BlindLevel{{Blinds: poker.Blinds{SmallBlind: 5, BigBlind: 10}}},
+	}
+	tournamentResponse := getTournamentResponse{}
+	readResponse(serveTestRequest(gameManager.CreateTournament, createReq).Result(), &tournamentResponse)
+	tournamentID := tournamentResponse.TournamentID
+
+	entrants := make([]registerResponse, 0)
+	for i := 0; i < 10; i++ {
+		registerReq := registerRequest{TournamentID: tournamentID, Passphrase: createReq.Passphrase, Name: "foo"}
+		registerResp := registerResponse{}
+		readResponse(serveTestRequest(gameManager.Register, registerReq).Result(), &registerResp)
+		entrants = append(entrants, registerResp)
+	}
+
+	startReq := getTournamentRequest{TournamentID: tournamentID, Passphrase: createReq.Passphrase}
+	readResponse(serveTestRequest(gameManager.StartTournament, startReq).Result(), &tournamentResponse)
+	if !tournamentResponse.Started || len(tournamentResponse.Tables) != 2 || len(tournamentResponse.Tables[1].Players) != 5 {
+		t.Fatal("Ten entrants should be split between two tables")
+	}
+
+	handReq := entrantRequest{TournamentID: tournamentID, Passphrase: createReq.Passphrase, Entrant: 1, Secret: entrants[0].Secret}
+	if recorder := serveTestRequest(gameManager.StartTournamentHand, handReq); recorder.Code != http.StatusForbidden && entrants[0].Secret != entrants[1].Secret {
+		t.Error("Entrants can only act for themselves")
+	}
+
+	handReq.Secret = entrants[1].Secret
+	recorder := serveTestRequest(gameManager.StartTournamentHand, handReq)
+	if recorder.Code != http.StatusOK {
+		t.Fatal(recorder.Body.String())
+	}
+	hand := poker.HandView{}
+	readResponse(recorder.Result(), &hand)
+	if len(hand.Seats) != 5 || len(hand.Seats[0].HoleCards) != 2 {
+		t.Error("Entrant 1 is in seat 0 of table 1")
+	}
+}
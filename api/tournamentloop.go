@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// tournamentCommand is something a request does to a tournament, which is run on its loop
+type tournamentCommand func(tournament *poker.MultiTableTournament)
+
+// tournamentLoop runs a tournament on its own goroutine, the same way a gameLoop runs a game.
+// Every table of the tournament is played on the one loop, since players move between them
+type tournamentLoop struct {
+	tournamentID int
+	// passphrase never changes, so it can be checked without going through the loop
+	passphrase string
+	commands   chan func(tournament *poker.MultiTableTournament) error
+	stop       chan struct{}
+	stopOnce   sync.Once
+	done       chan struct{}
+}
+
+func (manager *GameManager) startTournamentLoop(tournamentID int, tournament *poker.MultiTableTournament, passphrase string) *tournamentLoop {
+	loop := &tournamentLoop{
+		tournamentID: tournamentID,
+		passphrase:   passphrase,
+		commands:     make(chan func(tournament *poker.MultiTableTournament) error),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go manager.runTournamentLoop(loop, tournament)
+	return loop
+}
+
+func (manager *GameManager) runTournamentLoop(loop *tournamentLoop, tournament *poker.MultiTableTournament) {
+	defer close(loop.done)
+
+	ticker := time.NewTicker(gameTickInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case run := <-loop.commands:
+			err = run(tournament)
+		case <-ticker.C:
+			err = loop.safely(func() { updateTournament(tournament) })
+		case <-loop.stop:
+			return
+		}
+
+		if err != nil {
+			manager.removeTournament(loop)
+			return
+		}
+	}
+}
+
+// updateTournament acts for players at any table who ran out of time. It needs to run on the
+// tournament's loop
+func updateTournament(tournament *poker.MultiTableTournament) {
+	for _, table := range tournament.Tables() {
+		for {
+			if _, ok := tournament.EnforceShotClock(table); !ok {
+				break
+			}
+		}
+	}
+}
+
+// safely runs step, turning a panic into an error so one broken tournament can't take down the
+// server
+func (loop *tournamentLoop) safely(step func()) (err error) {
+	defer func() {
+		if panicked := recover(); panicked != nil {
+			log.Printf("Tournament %d panicked: %v\n%s", loop.tournamentID, panicked, debug.Stack())
+			err = fmt.Errorf("Tournament %d ran into a problem and has been closed", loop.tournamentID)
+		}
+	}()
+	step()
+	return nil
+}
+
+// run has the loop run a command, and waits for it to finish. Returns errGameClosed if the
+// tournament has been closed and the command never ran, or an error if the command panicked
+func (loop *tournamentLoop) run(run tournamentCommand) error {
+	finished := make(chan error, 1)
+	select {
+	case loop.commands <- func(tournament *poker.MultiTableTournament) error {
+		err := loop.safely(func() { run(tournament) })
+		finished <- err
+		return err
+	}:
+	case <-loop.done:
+		return errGameClosed
+	}
+
+	return <-finished
+}
+
+// do runs a request's command on the loop. The command responds to the request, unless the
+// tournament has been closed or the command panicked, which are responded to here
+func (loop *tournamentLoop) do(w http.ResponseWriter, r *http.Request, run tournamentCommand) bool {
+	err := loop.run(run)
+	if err == errGameClosed {
+		sendError(w, r, http.StatusGone, newRequestError(CodeGameClosed, "tournamentID", "Tournament %d has been closed", loop.tournamentID))
+	} else if err != nil {
+		sendError(w, r, http.StatusInternalServerError, err)
+	}
+	return err == nil
+}
+
+// close stops the loop, and waits for the command it is running to finish
+func (loop *tournamentLoop) close() {
+	loop.stopOnce.Do(func() { close(loop.stop) })
+	<-loop.done
+}
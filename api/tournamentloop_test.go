@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func TestTournamentLoopTicks(t *testing.T) {
+	gameManager := NewGameManager()
+	defer gameManager.Close()
+	createReq := createTournamentRequest{
+		Passphrase:   "foobar",
+		StarterChips: 1000,
+		Levels:       []poker.BlindLevel{{Blinds: poker.Blinds{SmallBlind: 5, BigBlind: 10}}},
+		ShotClock:    poker.ShotClock{Action: time.Nanosecond},
+	}
+	tournamentResponse := getTournamentResponse{}
+	readResponse(serveTestRequest(gameManager.CreateTournament, createReq).Result(), &tournamentResponse)
+	tournamentID := tournamentResponse.TournamentID
+
+	entrants := make([]registerResponse, 0)
+	for i := 0; i < 3; i++ {
+		registerReq := registerRequest{TournamentID: tournamentID, Passphrase: createReq.Passphrase, Name: "foo"}
+		registerResp := registerResponse{}
+		readResponse(serveTestRequest(gameManager.Register, registerReq).Result(), &registerResp)
+		entrants = append(entrants, registerResp)
+	}
+	startReq := getTournamentRequest{TournamentID: tournamentID, Passphrase: createReq.Passphrase}
+	serveTestRequest(gameManager.StartTournament, startReq)
+
+	handReq := entrantRequest{TournamentID: tournamentID, Passphrase: createReq.Passphrase, Entrant: entrants[0].Entrant.ID, Secret: entrants[0].Secret}
+	if recorder := serveTestRequest(gameManager.StartTournamentHand, handReq); recorder.Code != http.StatusOK {
+		t.Fatal(recorder.Body.String())
+	}
+
+	// Nobody makes a request, so it is the loop's tick which acts for whoever ran out of time
+	loop, err := gameManager.findTournament(tournamentID, createReq.Passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * gameTickInterval)
+	for {
+		timedOut := false
+		loop.run(func(tournament *poker.MultiTableTournament) {
+			for _, table := range tournament.Tables() {
+				game, _ := tournament.Table(table)
+				hand, ok := game.HandView(-1)
+				for _, seat := range hand.Seats {
+					timedOut = timedOut || !ok || seat.Folded
+				}
+			}
+		})
+		if timedOut {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("The loop never ticked")
+		}
+		time.Sleep(gameTickInterval / 10)
+	}
+}
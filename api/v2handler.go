@@ -0,0 +1,206 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+// v2GamesPath is where the v2 game resources live
+const v2GamesPath = "/api/v2/games"
+
+// The v2 API takes credentials in headers instead of the body, so reading a resource is a
+// plain GET
+const (
+	passphraseHeader = "X-Passphrase"
+	secretHeader     = "X-Player-Secret"
+)
+
+type addPlayerV2Request struct {
+	Name  string `json:"name"`
+	Seat  int    `json:"seat"`
+	Claim int    `json:"claim"`
+}
+
+type actV2Request struct {
+	Seat   int          `json:"seat"`
+	Action poker.Action `json:"action"`
+}
+
+// GamesV2 routes the v2 game resources, which are addressed by their path:
+//
+//	POST   /api/v2/games                      creates a game
+//	GET    /api/v2/games/{id}                 gets a game
+//	POST   /api/v2/games/{id}/players         adds a player
+//	DELETE /api/v2/games/{id}/players/{seat}  has a player leave the table
+//	POST   /api/v2/games/{id}/actions         takes an action for the player to act
+func (manager *GameManager) GamesV2(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, v2GamesPath), "/")
+	if path == "" {
+		if allowMethod(w, r, "POST") {
+			manager.createGameV2(w, r)
+		}
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	gameID := parts[0]
+	switch {
+	case len(parts) == 1:
+		if allowMethod(w, r, "GET") {
+			manager.getGameV2(w, r, gameID)
+		}
+	case len(parts) == 2 && parts[1] == "players":
+		if allowMethod(w, r, "POST") {
+			manager.addPlayerV2(w, r, gameID)
+		}
+	case len(parts) == 3 && parts[1] == "players":
+		seat, err := strconv.Atoi(parts[2])
+		if err != nil {
+			sendError(w, r, http.StatusNotFound, newRequestError(CodeNotFound, "", "No player at %s", r.URL.Path))
+			return
+		}
+		if allowMethod(w, r, "DELETE") {
+			manager.removePlayerV2(w, r, gameID, seat)
+		}
+	case len(parts) == 2 && parts[1] == "actions":
+		if allowMethod(w, r, "POST") {
+			manager.actV2(w, r, gameID)
+		}
+	default:
+		sendError(w, r, http.StatusNotFound, newRequestError(CodeNotFound, "", "Nothing at %s", r.URL.Path))
+	}
+}
+
+// allowMethod makes sure the request uses the only method the resource allows
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		methodNotAllowed(w, r, method)
+		return false
+	}
+	return true
+}
+
+func (manager *GameManager) createGameV2(w http.ResponseWriter, r *http.Request) {
+	create := CreateGameRequest{}
+	if ok := decodeJSONBody(w, r, &create); !ok {
+		return
+	}
+
+	game, err := poker.NewGameWithOptions(create.options(manager.clock))
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	loop, err := manager.addGame(&game, create.Passphrase, create.Private)
+	if err != nil {
+		sendError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	loop.do(w, r, func(game *poker.Game) {
+		w.Header().Set("Location", fmt.Sprintf("%s/%s", v2GamesPath, loop.gameID))
+		sendJSONStatus(w, r, http.StatusCreated, CreateGameResponse{GetGameResponse: newGetGameResponse(loop.gameID, game), HostToken: loop.hostToken})
+	})
+}
+
+func (manager *GameManager) getGameV2(w http.ResponseWriter, r *http.Request, gameID string) {
+	loop, ok := manager.findGameV2(w, r, gameID)
+	if !ok {
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		manager.updateGame(gameID, game)
+
+		sendJSONResponse(w, r, newGetGameResponse(gameID, game))
+	})
+}
+
+func (manager *GameManager) addPlayerV2(w http.ResponseWriter, r *http.Request, gameID string) {
+	add := addPlayerV2Request{}
+	if ok := decodeJSONBody(w, r, &add); !ok {
+		return
+	}
+
+	loop, ok := manager.findGameV2(w, r, gameID)
+	if !ok {
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		player, err := game.ClaimSeat(add.Name, add.Seat, add.Claim)
+		if err != nil {
+			sendError(w, r, http.StatusConflict, err)
+			return
+		}
+
+		w.Header().Set("Location", fmt.Sprintf("%s/%s/players/%d", v2GamesPath, gameID, player.Seat))
+		sendJSONStatus(w, r, http.StatusCreated, AddPlayerResponse{Player: player, Secret: player.Secret()})
+	})
+}
+
+func (manager *GameManager) removePlayerV2(w http.ResponseWriter, r *http.Request, gameID string, seat int) {
+	loop, ok := manager.findGameV2(w, r, gameID)
+	if !ok {
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if !resolvePlayerV2(w, r, game, seat) {
+			return
+		}
+
+		ledgerEntry, err := game.LeaveTable(seat)
+		if err != nil {
+			sendError(w, r, http.StatusConflict, err)
+			return
+		}
+		manager.offerSeats(gameID, game)
+
+		sendJSONResponse(w, r, ledgerEntry)
+	})
+}
+
+func (manager *GameManager) actV2(w http.ResponseWriter, r *http.Request, gameID string) {
+	act := actV2Request{}
+	if ok := decodeJSONBody(w, r, &act); !ok {
+		return
+	}
+
+	loop, ok := manager.findGameV2(w, r, gameID)
+	if !ok {
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		if !resolvePlayerV2(w, r, game, act.Seat) {
+			return
+		}
+
+		manager.updateGame(gameID, game)
+		err := game.Act(act.Seat, act.Action)
+		if err != nil {
+			sendError(w, r, http.StatusConflict, err)
+			return
+		}
+
+		hand, _ := game.HandView(act.Seat)
+		sendJSONResponse(w, r, hand)
+	})
+}
+
+// resolvePlayerV2 makes sure the request's secret header is for the player in seat
+func resolvePlayerV2(w http.ResponseWriter, r *http.Request, game *poker.Game, seat int) bool {
+	secret, err := strconv.Atoi(r.Header.Get(secretHeader))
+	if err != nil {
+		err = newRequestError(CodePlayerNotFound, secretHeader, "Could not find player")
+	} else {
+		err = resolvePlayer(game, seat, secret)
+	}
+	if err != nil {
+		sendError(w, r, http.StatusForbidden, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func serveV2Request(manager *GameManager, method string, path string, val interface{}, headers map[string]string) *httptest.ResponseRecorder {
+	req := createTestRequest(method, val)
+	req.URL.Path = path
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	manager.GamesV2(recorder, req)
+	return recorder
+}
+
+func TestGamesV2Api(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	recorder := serveV2Request(&gameManager, "POST", "/api/v2/games", gameReq, nil)
+	gameResponse := GetGameResponse{}
+	readResponse(recorder.Result(), &gameResponse)
+	if recorder.Code != http.StatusCreated || recorder.Header().Get("Location") != "/api/v2/games/"+gameResponse.GameID {
+		t.Fatal(recorder.Code, recorder.Header())
+	}
+	// The result only has the headers which were set before the status was written
+	if contentType := recorder.Result().Header.Get("Content-Type"); contentType != "application/json" {
+		t.Error(contentType)
+	}
+
+	gamePath := recorder.Header().Get("Location")
+	auth := map[string]string{passphraseHeader: "foobar"}
+	wrong := ErrorResponse{}
+	readResponse(serveV2Request(&gameManager, "GET", gamePath, nil, map[string]string{passphraseHeader: "wrong"}).Result(), &wrong)
+	missing := ErrorResponse{}
+	recorder = serveV2Request(&gameManager, "GET", "/api/v2/games/nothing", nil, map[string]string{passphraseHeader: "wrong"})
+	readResponse(recorder.Result(), &missing)
+	if recorder.Code != http.StatusNotFound || wrong.Code != missing.Code ||
+		strings.Replace(wrong.Message, gameResponse.GameID, "nothing", 1) != missing.Message {
+		t.Error("Wrong passphrase looks like a missing game", wrong, missing)
+	}
+	if recorder = serveV2Request(&gameManager, "PUT", gamePath, nil, auth); recorder.Code != http.StatusMethodNotAllowed || recorder.Header().Get("Allow") != "GET" {
+		t.Error(recorder.Code)
+	}
+	for _, path := range []string{gamePath + "/nothing", gamePath + "/players/nobody"} {
+		recorder = serveV2Request(&gameManager, "GET", path, nil, auth)
+		notFound := ErrorResponse{}
+		readResponse(recorder.Result(), &notFound)
+		if recorder.Code != http.StatusNotFound || notFound.Code != CodeNotFound || notFound.RequestID == "" {
+			t.Error(path, recorder.Code, notFound)
+		}
+	}
+
+	secrets := make(map[int]int)
+	for _, seat := range []int{0, 1} {
+		recorder = serveV2Request(&gameManager, "POST", gamePath+"/players", addPlayerV2Request{Name: "foo", Seat: seat}, auth)
+		if recorder.Code != http.StatusCreated || recorder.Header().Get("Location") != fmt.Sprintf("%s/players/%d", gamePath, seat) {
+			t.Fatal(recorder.Code, recorder.Header())
+		}
+		playerResponse := AddPlayerResponse{}
+		readResponse(recorder.Result(), &playerResponse)
+		secrets[seat] = playerResponse.Secret
+	}
+	if recorder = serveV2Request(&gameManager, "POST", gamePath+"/players", addPlayerV2Request{Name: "foo", Seat: 1}, auth); recorder.Code != http.StatusConflict {
+		t.Error("Seat 1 is taken")
+	}
+
+	// Hands are still started with v1
+	startReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: "foobar", Seat: 0, Secret: secrets[0]}
+	serveTestRequest(gameManager.StartHand, startReq)
+
+	actReq := actV2Request{Seat: 0, Action: poker.Action{Type: poker.Fold}}
+	if recorder = serveV2Request(&gameManager, "POST", gamePath+"/actions", actReq, auth); recorder.Code != http.StatusForbidden {
+		t.Error("Actions need the player's secret")
+	}
+	otherAuth := map[string]string{passphraseHeader: "foobar", secretHeader: strconv.Itoa(secrets[1])}
+	if recorder = serveV2Request(&gameManager, "POST", gamePath+"/actions", actV2Request{Seat: 1, Action: actReq.Action}, otherAuth); recorder.Code != http.StatusConflict {
+		t.Error("Seat 1 acts out of turn", recorder.Code)
+	}
+	playerAuth := map[string]string{passphraseHeader: "foobar", secretHeader: strconv.Itoa(secrets[0])}
+	raiseReq := actV2Request{Seat: 0, Action: poker.Action{Type: poker.Raise, Amount: 1}}
+	recorder = serveV2Request(&gameManager, "POST", gamePath+"/actions", raiseReq, playerAuth)
+	invalid := ErrorResponse{}
+	readResponse(recorder.Result(), &invalid)
+	if recorder.Code != http.StatusBadRequest || invalid.Code != CodeInvalidField || invalid.Field != "action" {
+		t.Error("Raises have to be big enough", recorder.Code, invalid)
+	}
+	if recorder = serveV2Request(&gameManager, "POST", gamePath+"/actions", actReq, playerAuth); recorder.Code != http.StatusOK {
+		t.Fatal(recorder.Body.String())
+	}
+	if recorder = serveV2Request(&gameManager, "POST", gamePath+"/actions", actReq, playerAuth); recorder.Code != http.StatusConflict {
+		t.Error("Hand is already over")
+	}
+
+	if recorder = serveV2Request(&gameManager, "DELETE", gamePath+"/players/0", nil, playerAuth); recorder.Code != http.StatusOK {
+		t.Fatal(recorder.Body.String())
+	}
+	gameResponse = GetGameResponse{}
+	readResponse(serveV2Request(&gameManager, "GET", gamePath, nil, auth).Result(), &gameResponse)
+	if len(gameResponse.Players) != 1 || gameResponse.Players[1].Chips != 105 {
+		t.Error(gameResponse.Players)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+type waitlistRequest struct {
+	GameID     string `json:"gameID"`
+	Passphrase string `json:"passphrase"`
+	Name       string `json:"name"`
+	// Leave takes the name off the waitlist, or turns down the seat offered to them. It takes
+	// the secret they joined with
+	Leave  bool `json:"leave"`
+	Secret int  `json:"secret"`
+}
+
+// waitlistResponse is the game, and the secret for whoever just joined the waitlist. The secret
+// is what leaving takes, and is the claim for sitting in the seat they get offered
+type waitlistResponse struct {
+	GetGameResponse
+	Secret int `json:"secret,omitempty"`
+}
+
+// Waitlist puts someone in line for a seat in a full game, or takes them out of it. When a seat
+// opens up, a seat-offered event is sent to the game's event stream
+func (manager *GameManager) Waitlist(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		methodNotAllowed(w, r, "POST")
+		return
+	}
+
+	waitlist := waitlistRequest{}
+	if ok := decodeJSONBody(w, r, &waitlist); !ok {
+		return
+	}
+
+	loop, err := manager.findGame(waitlist.GameID, waitlist.Passphrase)
+	if err != nil {
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	loop.do(w, r, func(game *poker.Game) {
+		secret := 0
+		if waitlist.Leave {
+			err = game.LeaveWaitlist(waitlist.Name, waitlist.Secret)
+		} else {
+			secret, err = game.JoinWaitlist(waitlist.Name)
+		}
+		if err != nil {
+			sendError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		manager.offerSeats(waitlist.GameID, game)
+
+		sendJSONResponse(w, r, waitlistResponse{GetGameResponse: newGetGameResponse(waitlist.GameID, game), Secret: secret})
+	})
+}
+
+// offerSeats offers any open seats in game to the waitlist, and lets everyone following the
+// game know. It needs to run on the game's loop
+func (manager *GameManager) offerSeats(gameID string, game *poker.Game) {
+	for _, offer := range game.OfferSeats() {
+		manager.events.publish(gameID, event{Name: "seat-offered", Data: offer})
+	}
+}
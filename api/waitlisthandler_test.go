@@ -0,0 +1,75 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func TestWaitlistApi(t *testing.T) {
+	gameManager := NewGameManager()
+	gameReq := CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}
+	gameResponse := GetGameResponse{}
+	readResponse(serveTestRequest(gameManager.CreateGame, gameReq).Result(), &gameResponse)
+
+	secrets := make(map[int]int)
+	for seat := 0; seat < poker.DefaultTableSize; seat++ {
+		playerReq := AddPlayerRequest{Name: fmt.Sprint("player", seat), Seat: seat, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+		playerResponse := AddPlayerResponse{}
+		readResponse(serveTestRequest(gameManager.AddPlayer, playerReq).Result(), &playerResponse)
+		secrets[seat] = playerResponse.Secret
+	}
+
+	waitlistReq := waitlistRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Name: "alice"}
+	waitlistResp := waitlistResponse{}
+	readResponse(serveTestRequest(gameManager.Waitlist, waitlistReq).Result(), &waitlistResp)
+	if len(waitlistResp.Waitlist) != 1 || waitlistResp.Waitlist[0] != "alice" || waitlistResp.Secret == 0 {
+		t.Fatal(waitlistResp)
+	}
+	leaveWaitlistReq := waitlistRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Name: "alice", Leave: true}
+	if recorder := serveTestRequest(gameManager.Waitlist, leaveWaitlistReq); recorder.Code != http.StatusForbidden {
+		t.Error("Only alice can take alice off the waitlist", recorder.Code)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(gameManager.Events))
+	defer server.Close()
+	if resp, err := http.Get(server.URL + "?gameID=" + gameResponse.GameID + "&passphrase=wrong"); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Error("Events need the passphrase")
+	}
+	resp, err := http.Get(fmt.Sprintf("%s?gameID=%s&passphrase=%s", server.URL, gameResponse.GameID, gameReq.Passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	leaveReq := PlayerRequest{GameID: gameResponse.GameID, Passphrase: gameReq.Passphrase, Seat: 5, Secret: secrets[5]}
+	if recorder := serveTestRequest(gameManager.LeaveTable, leaveReq); recorder.Code != http.StatusOK {
+		t.Fatal(recorder.Body.String())
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	name, _ := reader.ReadString('\n')
+	data, _ := reader.ReadString('\n')
+	offer := poker.SeatOffer{}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &offer); err != nil {
+		t.Fatal(err)
+	}
+	if name != "event: seat-offered\n" || offer.Seat != 5 || offer.Name != "alice" {
+		t.Error(name, offer)
+	}
+
+	playerReq := AddPlayerRequest{Name: "alice", Seat: 5, Passphrase: gameReq.Passphrase, GameID: gameResponse.GameID}
+	if recorder := serveTestRequest(gameManager.AddPlayer, playerReq); recorder.Code != http.StatusConflict {
+		t.Error("Taking the offered seat takes the waitlist secret", recorder.Code)
+	}
+	playerReq.Claim = waitlistResp.Secret
+	if recorder := serveTestRequest(gameManager.AddPlayer, playerReq); recorder.Code != http.StatusOK {
+		t.Error(recorder.Body.String())
+	}
+}
@@ -0,0 +1,212 @@
+// Package client is a Go client for the httpoker API, for writing bots and tools. It uses the
+// same request and response types as the api package, so the two can't drift apart
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/api"
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+const (
+	// DefaultRetries is how many times a request is retried after a transient failure
+	DefaultRetries = 3
+	// DefaultRetryWait is how long to wait before the first retry. It doubles each retry after
+	DefaultRetryWait = 100 * time.Millisecond
+)
+
+// Client makes requests to an httpoker server. The zero value isn't usable, use New
+type Client struct {
+	// BaseURL is where the server is, i.e. http://localhost:8080
+	BaseURL    string
+	HTTPClient *http.Client
+	Retries    int
+	RetryWait  time.Duration
+}
+
+// New makes a client for the server at baseURL
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: http.DefaultClient,
+		Retries:    DefaultRetries,
+		RetryWait:  DefaultRetryWait,
+	}
+}
+
+// Error is an error response from the server
+type Error struct {
+	StatusCode int
+	api.ErrorResponse
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", err.StatusCode, err.Code, err.Message)
+}
+
+// CreateGame creates a game
+func (client *Client) CreateGame(ctx context.Context, request api.CreateGameRequest) (api.CreateGameResponse, error) {
+	response := api.CreateGameResponse{}
+	err := client.post(ctx, "/api/v1/game/create", request, &response, false)
+	return response, err
+}
+
+// Game gets the state of a game
+func (client *Client) Game(ctx context.Context, gameID string, passphrase string) (api.GetGameResponse, error) {
+	response := api.GetGameResponse{}
+	request := api.GetGameRequest{GameID: gameID, Passphrase: passphrase}
+	err := client.post(ctx, "/api/v1/game/status", request, &response, true)
+	return response, err
+}
+
+// AddPlayer sits a player down at a game. The response has the secret needed for everything
+// the player does after
+func (client *Client) AddPlayer(ctx context.Context, request api.AddPlayerRequest) (api.AddPlayerResponse, error) {
+	response := api.AddPlayerResponse{}
+	err := client.post(ctx, "/api/v1/game/add-player", request, &response, false)
+	return response, err
+}
+
+// StartHand deals the next hand
+func (client *Client) StartHand(ctx context.Context, request api.PlayerRequest) (poker.HandView, error) {
+	response := poker.HandView{}
+	err := client.post(ctx, "/api/v1/game/start-hand", request, &response, false)
+	return response, err
+}
+
+// Hand gets the current hand as the player sees it
+func (client *Client) Hand(ctx context.Context, request api.PlayerRequest) (poker.HandView, error) {
+	response := poker.HandView{}
+	err := client.post(ctx, "/api/v1/game/hand", request, &response, true)
+	return response, err
+}
+
+// Act takes an action for the player
+func (client *Client) Act(ctx context.Context, request api.ActRequest) (poker.HandView, error) {
+	response := poker.HandView{}
+	err := client.post(ctx, "/api/v1/game/act", request, &response, false)
+	return response, err
+}
+
+// SitOut sits the player out, or back in when the request cancels it
+func (client *Client) SitOut(ctx context.Context, request api.SitOutRequest) (api.GetGameResponse, error) {
+	response := api.GetGameResponse{}
+	err := client.post(ctx, "/api/v1/game/sit-out", request, &response, false)
+	return response, err
+}
+
+// Leave has the player leave the table, and gets what they cashed out with
+func (client *Client) Leave(ctx context.Context, request api.PlayerRequest) (poker.LedgerEntry, error) {
+	response := poker.LedgerEntry{}
+	err := client.post(ctx, "/api/v1/game/leave", request, &response, false)
+	return response, err
+}
+
+// Invite makes an invite link to a game, which holds a seat when the request has one
+func (client *Client) Invite(ctx context.Context, request api.InviteRequest) (api.InviteResponse, error) {
+	response := api.InviteResponse{}
+	err := client.post(ctx, "/api/v1/game/invite", request, &response, false)
+	return response, err
+}
+
+// Join gets the game an invite is for. The invite is used in place of the game's passphrase
+func (client *Client) Join(ctx context.Context, invite string) (api.JoinResponse, error) {
+	response := api.JoinResponse{}
+	err := client.post(ctx, "/api/v1/game/join", api.JoinRequest{Invite: invite}, &response, true)
+	return response, err
+}
+
+// Spectate starts watching a game without a seat, with an invite to spectate it. The response
+// has the token to watch it with
+func (client *Client) Spectate(ctx context.Context, request api.SpectateRequest) (api.SpectateResponse, error) {
+	response := api.SpectateResponse{}
+	err := client.post(ctx, "/api/v1/game/spectate", request, &response, false)
+	return response, err
+}
+
+// Watch gets the game as a spectator sees it
+func (client *Client) Watch(ctx context.Context, request api.WatchRequest) (api.GetGameResponse, error) {
+	response := api.GetGameResponse{}
+	err := client.post(ctx, "/api/v1/game/watch", request, &response, true)
+	return response, err
+}
+
+// post sends request and decodes the response into response. Reads are retried after any
+// transient failure, but changes are only retried when the server turned them away without
+// handling them, so an action is never taken twice
+func (client *Client) post(ctx context.Context, path string, request interface{}, response interface{}, read bool) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	wait := client.RetryWait
+	for attempt := 0; ; attempt++ {
+		retry, err := client.try(ctx, path, body, response, read)
+		if err == nil || !retry || attempt >= client.Retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// try makes one attempt at a request, and tells if it is worth trying again when it fails
+func (client *Client) try(ctx context.Context, path string, body []byte, response interface{}, read bool) (bool, error) {
+	req, err := http.NewRequest("POST", client.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		// The request may have been handled before the connection went away
+		return read && ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return read, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return retryable(resp.StatusCode, read), newError(resp.StatusCode, data)
+	}
+	return false, json.Unmarshal(data, response)
+}
+
+func retryable(status int, read bool) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return read
+	}
+	return false
+}
+
+func newError(status int, body []byte) error {
+	err := &Error{StatusCode: status}
+	if json.Unmarshal(body, &err.ErrorResponse) != nil || err.Code == "" {
+		// Something in front of the server answered, so there is no envelope
+		err.Code = api.ErrorCode(strings.ToLower(strings.Replace(http.StatusText(status), " ", "_", -1)))
+		err.Message = strings.TrimSpace(string(body))
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brian-a-esch/httpoker/api"
+	"github.com/brian-a-esch/httpoker/poker"
+)
+
+func newTestServer(manager *api.GameManager) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/game/status", manager.Game)
+	mux.HandleFunc("/api/v1/game/create", manager.CreateGame)
+	mux.HandleFunc("/api/v1/game/add-player", manager.AddPlayer)
+	mux.HandleFunc("/api/v1/game/start-hand", manager.StartHand)
+	mux.HandleFunc("/api/v1/game/hand", manager.Hand)
+	mux.HandleFunc("/api/v1/game/act", manager.Act)
+	mux.HandleFunc("/api/v1/game/sit-out", manager.SitOut)
+	mux.HandleFunc("/api/v1/game/leave", manager.LeaveTable)
+	mux.HandleFunc("/api/v1/game/events", manager.Events)
+	mux.HandleFunc("/api/v1/game/invite", manager.Invite)
+	mux.HandleFunc("/api/v1/game/join", manager.Join)
+	mux.HandleFunc("/api/v1/game/spectate", manager.Spectate)
+	mux.HandleFunc("/api/v1/game/watch", manager.Watch)
+	return httptest.NewServer(mux)
+}
+
+func TestClient(t *testing.T) {
+	manager := api.NewGameManager()
+	server := newTestServer(&manager)
+	defer server.Close()
+	client := New(server.URL)
+	ctx := context.Background()
+
+	created, err := client.CreateGame(ctx, api.CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10})
+	if err != nil || created.HostToken == "" {
+		t.Fatal(err, created)
+	}
+	game := created.GetGameResponse
+
+	secrets := make([]int, 0)
+	for seat := 0; seat < 2; seat++ {
+		player, err := client.AddPlayer(ctx, api.AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: "foobar", GameID: game.GameID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		secrets = append(secrets, player.Secret)
+	}
+
+	_, err = client.AddPlayer(ctx, api.AddPlayerRequest{Name: "bar", Seat: 0, Passphrase: "foobar", GameID: game.GameID})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict || apiErr.Code != api.CodeSeatTaken {
+		t.Error(err)
+	}
+
+	hand, err := client.StartHand(ctx, api.PlayerRequest{GameID: game.GameID, Passphrase: "foobar", Seat: 0, Secret: secrets[0]})
+	if err != nil || len(hand.Seats[0].HoleCards) != 2 {
+		t.Fatal(err, hand)
+	}
+
+	toAct := hand.ToAct
+	act := api.ActRequest{GameID: game.GameID, Passphrase: "foobar", Seat: toAct, Secret: secrets[toAct], Action: poker.Action{Type: poker.Fold}}
+	if hand, err = client.Act(ctx, act); err != nil || !hand.Over {
+		t.Error(err, hand)
+	}
+
+	if _, err := client.Game(ctx, game.GameID, "wrong"); !errors.As(err, &apiErr) || apiErr.Code != api.CodeGameNotFound {
+		t.Error(err)
+	}
+	if game, err = client.Game(ctx, game.GameID, "foobar"); err != nil || len(game.Players) != 2 {
+		t.Error(err, game.Players)
+	}
+
+	entry, err := client.Leave(ctx, api.PlayerRequest{GameID: game.GameID, Passphrase: "foobar", Seat: 1, Secret: secrets[1]})
+	if err != nil || !entry.Left {
+		t.Error(err, entry)
+	}
+
+	seat := 1
+	invite, err := client.Invite(ctx, api.InviteRequest{GameID: game.GameID, Passphrase: "foobar", Seat: &seat, Name: "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	join, err := client.Join(ctx, invite.Invite)
+	if err != nil || join.Game.Reservations[1].Name != "bar" {
+		t.Error(err, join)
+	}
+	if _, err := client.Game(ctx, game.GameID, invite.Invite); err != nil {
+		t.Error("The invite works in place of the passphrase", err)
+	}
+
+	invite, err = client.Invite(ctx, api.InviteRequest{GameID: game.GameID, Passphrase: "foobar", Spectate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spectate, err := client.Spectate(ctx, api.SpectateRequest{Invite: invite.Invite})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game, err = client.Watch(ctx, api.WatchRequest{GameID: game.GameID, Token: spectate.Token}); err != nil || game.Spectators != 1 {
+		t.Error(err, game.Spectators)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	manager := api.NewGameManager()
+	failures := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		manager.CreateGame(w, r)
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	client.RetryWait = time.Millisecond
+	if _, err := client.CreateGame(context.Background(), api.CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10}); err != nil {
+		t.Error(err)
+	}
+
+	failures = 2
+	client.Retries = 1
+	_, err := client.CreateGame(context.Background(), api.CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Error("Should give up after one retry", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Game(ctx, "nothing", "foobar"); !errors.Is(err, context.Canceled) {
+		t.Error(err)
+	}
+}
+
+func TestClientEvents(t *testing.T) {
+	manager := api.NewGameManager()
+	server := newTestServer(&manager)
+	defer server.Close()
+	client := New(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	create := api.CreateGameRequest{Passphrase: "foobar", StarterChips: 100, BlindSize: 10, ShotClock: poker.ShotClock{Action: time.Nanosecond}}
+	game, err := client.CreateGame(ctx, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := make([]int, 0)
+	for seat := 0; seat < 2; seat++ {
+		player, err := client.AddPlayer(ctx, api.AddPlayerRequest{Name: "foo", Seat: seat, Passphrase: "foobar", GameID: game.GameID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		secrets = append(secrets, player.Secret)
+	}
+
+	if _, err := client.Events(ctx, game.GameID, "wrong"); err == nil {
+		t.Error("Events need the passphrase")
+	}
+	events, err := client.Events(ctx, game.GameID, "foobar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hand, err := client.StartHand(ctx, api.PlayerRequest{GameID: game.GameID, Passphrase: "foobar", Seat: 0, Secret: secrets[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stream catches the player to act running out of time on its next tick
+	select {
+	case e := <-events:
+		timedOut := struct {
+			Seat int `json:"seat"`
+		}{}
+		if err := json.Unmarshal(e.Data, &timedOut); err != nil || e.Name != "timed-out" || timedOut.Seat != hand.ToAct {
+			t.Error(e.Name, string(e.Data))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No event")
+	}
+
+	cancel()
+	for range events {
+	}
+}
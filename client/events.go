@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Event is a server-sent event from a game. Data is left as JSON since its shape depends on
+// the event
+type Event struct {
+	Name string
+	Data json.RawMessage
+}
+
+// Events follows a game's events until ctx is done or the server ends the stream, when the
+// channel is closed
+func (client *Client) Events(ctx context.Context, gameID string, passphrase string) (<-chan Event, error) {
+	query := url.Values{}
+	query.Set("gameID", gameID)
+	query.Set("passphrase", passphrase)
+
+	req, err := http.NewRequest("GET", client.BaseURL+"/api/v1/game/events?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, newError(resp.StatusCode, body)
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+		readEvents(ctx, bufio.NewScanner(resp.Body), events)
+	}()
+	return events, nil
+}
+
+// readEvents parses the event stream, where each event is a few lines of fields ended by a
+// blank line
+func readEvents(ctx context.Context, scanner *bufio.Scanner, events chan<- Event) {
+	e := Event{}
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if e.Name == "" && e.Data == nil {
+				continue
+			}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+			e = Event{}
+		case strings.HasPrefix(line, "event:"):
+			e.Name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			e.Data = append(e.Data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/brian-a-esch/httpoker/api"
 )
@@ -27,13 +32,68 @@ func main() {
 	mux.Handle("/", fs)
 	mux.HandleFunc("/api/v1/game/status", gameMangager.Game)
 	mux.HandleFunc("/api/v1/game/create", gameMangager.CreateGame)
-	mux.HandleFunc("/api/v1/game/add-player", gameMangager.CreateGame)
+	mux.HandleFunc("/api/v1/game/add-player", gameMangager.AddPlayer)
+	mux.HandleFunc("/api/v1/game/choose-game", gameMangager.ChooseGame)
+	mux.HandleFunc("/api/v1/game/start-hand", gameMangager.StartHand)
+	mux.HandleFunc("/api/v1/game/hand", gameMangager.Hand)
+	mux.HandleFunc("/api/v1/game/act", gameMangager.Act)
+	mux.HandleFunc("/api/v1/game/straddle", gameMangager.Straddle)
+	mux.HandleFunc("/api/v1/game/run-it", gameMangager.RunIt)
+	mux.HandleFunc("/api/v1/game/bomb-pot", gameMangager.BombPot)
+	mux.HandleFunc("/api/v1/game/rebuy", gameMangager.Rebuy)
+	mux.HandleFunc("/api/v1/game/sit-out", gameMangager.SitOut)
+	mux.HandleFunc("/api/v1/game/leave", gameMangager.LeaveTable)
+	mux.HandleFunc("/api/v1/game/reserve-seat", gameMangager.ReserveSeat)
+	mux.HandleFunc("/api/v1/game/waitlist", gameMangager.Waitlist)
+	mux.HandleFunc("/api/v1/game/events", gameMangager.Events)
+	mux.HandleFunc("/api/v1/game/ledger", gameMangager.Ledger)
+	mux.HandleFunc("/api/v1/game/ledger.csv", gameMangager.LedgerCSV)
+	mux.HandleFunc("/api/v1/game/invite", gameMangager.Invite)
+	mux.HandleFunc("/api/v1/game/join", gameMangager.Join)
+	mux.HandleFunc("/api/v1/game/spectate", gameMangager.Spectate)
+	mux.HandleFunc("/api/v1/game/watch", gameMangager.Watch)
+	mux.HandleFunc("/api/v1/game/spectator-rules", gameMangager.SpectatorRules)
+	mux.HandleFunc("/api/v1/lobby", gameMangager.Lobby)
+	mux.HandleFunc("/api/v1/tournament/status", gameMangager.Tournament)
+	mux.HandleFunc("/api/v1/tournament/create", gameMangager.CreateTournament)
+	mux.HandleFunc("/api/v1/tournament/register", gameMangager.Register)
+	mux.HandleFunc("/api/v1/tournament/start", gameMangager.StartTournament)
+	mux.HandleFunc("/api/v1/tournament/start-hand", gameMangager.StartTournamentHand)
+	mux.HandleFunc("/api/v1/tournament/act", gameMangager.TournamentAct)
+	mux.HandleFunc("/api/v1/tournament/rebuy", gameMangager.TournamentRebuy)
+	mux.HandleFunc("/api/v1/tournament/add-on", gameMangager.TournamentAddOn)
+	mux.HandleFunc("/api/v1/tournament/re-enter", gameMangager.TournamentReEnter)
+	mux.HandleFunc("/api/v2/games", gameMangager.GamesV2)
+	mux.HandleFunc("/api/v2/games/", gameMangager.GamesV2)
+	mux.HandleFunc("/api/v1/payouts", api.Payouts)
+	mux.HandleFunc("/api/v1/icm", api.ICM)
+	mux.HandleFunc(api.OpenAPIPath, api.OpenAPI)
 
 	serve := http.Server{
 		Addr:    config.hostport,
 		Handler: mux,
 	}
 
+	// Closing the games first ends their event streams, which would otherwise keep the server
+	// from shutting down
+	stopped := make(chan struct{})
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+
+		gameMangager.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := serve.Shutdown(ctx); err != nil {
+			log.Println(err)
+		}
+		close(stopped)
+	}()
+
 	log.Printf("Listening on %s\n", config.hostport)
-	log.Fatal(serve.ListenAndServe())
+	if err := serve.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	<-stopped
 }
@@ -0,0 +1,151 @@
+package poker
+
+import (
+	"fmt"
+)
+
+// DefaultRaiseCap is the number of bets and raises allowed in a fixed limit betting round
+const DefaultRaiseCap int = 4
+
+// BettingLimits are the bet sizes for the structures which limit bets. Zero values are
+// filled in with defaults based on the blinds
+type BettingLimits struct {
+	// SmallBet is the fixed limit bet for the early betting rounds, defaults to the big blind
+	SmallBet int `json:"smallBet"`
+	// BigBet is the fixed limit bet for the later betting rounds, defaults to twice the small bet
+	BigBet int `json:"bigBet"`
+	// RaiseCap is the number of bets and raises allowed each round in fixed limit
+	RaiseCap int `json:"raiseCap"`
+	// SpreadMin is the smallest bet or raise allowed in spread limit, defaults to the big blind
+	SpreadMin int `json:"spreadMin"`
+	// SpreadMax is the largest bet or raise allowed in spread limit
+	SpreadMax int `json:"spreadMax"`
+}
+
+func (limits *BettingLimits) fillDefaults(bigBlind int) {
+	if limits.SmallBet == 0 {
+		limits.SmallBet = bigBlind
+	}
+	if limits.BigBet == 0 {
+		limits.BigBet = 2 * limits.SmallBet
+	}
+	if limits.RaiseCap == 0 {
+		limits.RaiseCap = DefaultRaiseCap
+	}
+	if limits.SpreadMin == 0 {
+		limits.SpreadMin = bigBlind
+	}
+}
+
+func (limits *BettingLimits) validate(spreadLimit bool) error {
+	if limits.SmallBet < 0 || limits.BigBet < limits.SmallBet {
+		return fmt.Errorf("Fixed limit needs positive bet sizes, with the big bet at least the small bet")
+	}
+	if limits.RaiseCap < 1 {
+		return fmt.Errorf("Fixed limit needs a raise cap of at least 1")
+	}
+	if spreadLimit && (limits.SpreadMin <= 0 || limits.SpreadMax < limits.SpreadMin) {
+		return fmt.Errorf("Spread limit needs a positive spread, but got %d to %d", limits.SpreadMin, limits.SpreadMax)
+	}
+	return nil
+}
+
+// ActionType is the kind of action a player takes on their turn
+type ActionType int
+
+const (
+	Fold ActionType = iota
+	Check
+	Call
+	Bet
+	Raise
+	// Post is for forced bets like blinds, and can't be chosen by a player
+	Post
+	// Ante is dead money put in the pot before the hand, and can't be chosen by a player
+	Ante
+)
+
+var actionTypeNames = [...]string{
+	"fold",
+	"check",
+	"call",
+	"bet",
+	"raise",
+	"post",
+	"ante",
+}
+
+func (a ActionType) String() string {
+	return actionTypeNames[a]
+}
+
+// MarshalText lets an ActionType be serialized by its name
+func (a ActionType) MarshalText() ([]byte, error) {
+	if a < Fold || int(a) >= len(actionTypeNames) {
+		return nil, fmt.Errorf("Unknown action %d", a)
+	}
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText parses an ActionType from its name
+func (a *ActionType) UnmarshalText(text []byte) error {
+	for i, name := range actionTypeNames {
+		if name == string(text) {
+			*a = ActionType(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown action %q", string(text))
+}
+
+// Action is what a player does on their turn. For bets and raises, the amount is the total
+// the player is betting in the round, i.e. "raise to", not "raise by"
+type Action struct {
+	Type   ActionType `json:"type"`
+	Amount int        `json:"amount"`
+}
+
+// ActionOptions are the legal actions for a player whose turn it is
+type ActionOptions struct {
+	Seat       int  `json:"seat"`
+	ToCall     int  `json:"toCall"`
+	CanCheck   bool `json:"canCheck"`
+	CanRaise   bool `json:"canRaise"`
+	MinRaiseTo int  `json:"minRaiseTo"`
+	MaxRaiseTo int  `json:"maxRaiseTo"`
+}
+
+// raiseBounds computes the smallest and largest total a player can bet or raise to in the
+// current round, where a max of -1 means there is no limit. The player's stack is not taken
+// into account here
+func raiseBounds(structure BettingStructure, limits BettingLimits, bigBlind int, currentBet int, lastRaise int,
+	playerBet int, pot int, raises int, bigBetRound bool) (int, int) {
+	minRaise := lastRaise
+	if minRaise < bigBlind {
+		minRaise = bigBlind
+	}
+
+	switch structure {
+	case PotLimit:
+		// The player first calls, and then can raise the size of the pot including their call
+		toCall := currentBet - playerBet
+		return currentBet + minRaise, currentBet + pot + toCall
+	case FixedLimit:
+		size := limits.SmallBet
+		if bigBetRound {
+			size = limits.BigBet
+		}
+		// Raising to the next multiple of the bet also handles completing a bring in
+		return (raises + 1) * size, (raises + 1) * size
+	case SpreadLimit:
+		if minRaise < limits.SpreadMin {
+			minRaise = limits.SpreadMin
+		}
+		if minRaise > limits.SpreadMax {
+			minRaise = limits.SpreadMax
+		}
+		return currentBet + minRaise, currentBet + limits.SpreadMax
+	default:
+		return currentBet + minRaise, -1
+	}
+}
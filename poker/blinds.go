@@ -0,0 +1,100 @@
+package poker
+
+import (
+	"fmt"
+)
+
+// Straddles are the voluntary straddles players are allowed to post. A straddle is a blind
+// raise to twice the big blind, posted before the cards are dealt
+type Straddles struct {
+	// UnderTheGun lets the player left of the big blind straddle. They act last before the flop
+	UnderTheGun bool `json:"underTheGun"`
+	// Button is the Mississippi straddle, where action starts left of the big blind and skips
+	// the button, who acts last before the flop
+	Button bool `json:"button"`
+}
+
+// Blinds are the forced bets posted each hand
+type Blinds struct {
+	SmallBlind int `json:"smallBlind"`
+	BigBlind   int `json:"bigBlind"`
+	// Ante is posted by every player dealt in, unless BigBlindAnte is set
+	Ante int `json:"ante"`
+	// BigBlindAnte has the big blind post a single ante for the whole table. Stud games have no
+	// big blind, so everyone posts the ante instead
+	BigBlindAnte bool      `json:"bigBlindAnte"`
+	Straddles    Straddles `json:"straddles"`
+}
+
+func (blinds *Blinds) validate() error {
+	if blinds.SmallBlind <= 0 || blinds.BigBlind < blinds.SmallBlind {
+		return fmt.Errorf("Game needs positive blinds, with the big blind at least the small blind")
+	}
+	if blinds.Ante < 0 {
+		return fmt.Errorf("Game cannot have a negative ante")
+	}
+	return nil
+}
+
+// Blinds gets the forced bets for the game
+func (game *Game) Blinds() Blinds {
+	return game.blinds
+}
+
+// Straddler gets the seat of the player who will straddle next hand. Returns false if no
+// one is straddling
+func (game *Game) Straddler() (int, bool) {
+	return game.straddler, game.straddler != -1
+}
+
+// Straddle has the player in seat straddle the next hand. Only one player can straddle, and
+// they must be under the gun or on the button for the next hand, if the game allows it
+func (game *Game) Straddle(seat int) error {
+	if game.HandInProgress() {
+		return fmt.Errorf("Can only straddle before the hand is dealt")
+	}
+	if game.straddler != -1 {
+		return fmt.Errorf("Seat %d is already straddling", game.straddler)
+	}
+
+	upcoming, _ := game.UpcomingGame()
+	if upcoming.Betting == FixedLimit || rulesFor(upcoming.Variant).stud {
+		return fmt.Errorf("Cannot straddle in %s", upcoming)
+	}
+
+	if !game.canStraddle(seat, game.nextOccupiedSeat(game.button)) {
+		return fmt.Errorf("Seat %d is not in a position which can straddle", seat)
+	}
+
+	game.straddler = seat
+	return nil
+}
+
+// CancelStraddle takes back a straddle before the hand is dealt
+func (game *Game) CancelStraddle(seat int) error {
+	if game.straddler != seat {
+		return fmt.Errorf("Seat %d is not straddling", seat)
+	}
+
+	game.straddler = -1
+	return nil
+}
+
+// canStraddle tells if the player in seat can straddle when button has the button
+func (game *Game) canStraddle(seat int, button int) bool {
+	seats := make([]int, 0, game.tableSize)
+	for i := 1; i <= game.tableSize; i++ {
+		next := (button + i) % game.tableSize
+		if player, ok := game.players[next]; ok && player.dealtIn() {
+			seats = append(seats, next)
+		}
+	}
+
+	// Heads up there is no one to straddle
+	if len(seats) < 3 {
+		return false
+	}
+
+	straddles := game.blinds.Straddles
+	return (straddles.UnderTheGun && seats[2] == seat) || (straddles.Button && button == seat)
+}
@@ -0,0 +1,120 @@
+package poker
+
+import (
+	"testing"
+)
+
+func TestIndependentBlinds(t *testing.T) {
+	if _, err := NewGameWithOptions(GameOptions{StarterChips: 100, Blinds: Blinds{SmallBlind: 10, BigBlind: 5}}); err == nil {
+		t.Error("Small blind can't be bigger than the big blind")
+	}
+
+	game := newTestGame(t, GameOptions{Blinds: Blinds{SmallBlind: 5, BigBlind: 15}}, 0, 1, 2)
+	game.StartHand()
+
+	view, _ := game.HandView(-1)
+	if view.Seats[1].Bet != 5 || view.Seats[2].Bet != 15 || game.BlindSize() != 15 {
+		t.Error()
+	}
+}
+
+func TestAntes(t *testing.T) {
+	game := newTestGame(t, GameOptions{Blinds: Blinds{SmallBlind: 5, BigBlind: 10, Ante: 2}}, 0, 1, 2)
+	game.StartHand()
+
+	view, _ := game.HandView(-1)
+	if view.Pot != 21 || view.Seats[0].Bet != 0 || game.players[0].Chips != 98 {
+		t.Error("Antes are dead money, and don't count towards a bet")
+	}
+
+	options, _ := game.ActionOptions(0)
+	if options.ToCall != 10 {
+		t.Error()
+	}
+}
+
+func TestBigBlindAnte(t *testing.T) {
+	game := newTestGame(t, GameOptions{Blinds: Blinds{SmallBlind: 5, BigBlind: 10, Ante: 10, BigBlindAnte: true}}, 0, 1, 2)
+	setChips(&game, 2, 15)
+	game.StartHand()
+
+	// The big blind posts their blind before the ante when they are short
+	view, _ := game.HandView(-1)
+	if view.Pot != 20 || view.Seats[2].Bet != 10 || game.players[0].Chips != 100 || game.players[2].Chips != 0 {
+		t.Error(view.Pot, game.players)
+	}
+}
+
+func TestUnderTheGunStraddle(t *testing.T) {
+	game := newTestGame(t, GameOptions{Blinds: Blinds{SmallBlind: 5, BigBlind: 10, Straddles: Straddles{UnderTheGun: true}}}, 0, 1, 2, 3)
+
+	if err := game.Straddle(0); err == nil {
+		t.Error("Only under the gun can straddle")
+	}
+	if err := game.Straddle(3); err != nil {
+		t.Fatal(err)
+	}
+	if straddler, ok := game.Straddler(); !ok || straddler != 3 {
+		t.Error()
+	}
+	game.StartHand()
+
+	view, _ := game.HandView(-1)
+	if view.Seats[3].Bet != 20 || view.ToAct != 0 {
+		t.Error("Action starts left of the straddle")
+	}
+
+	options, _ := game.ActionOptions(0)
+	if options.ToCall != 20 || options.MinRaiseTo != 40 {
+		t.Error(options)
+	}
+
+	mustAct(t, &game, 0, Action{Type: Call})
+	mustAct(t, &game, 1, Action{Type: Call})
+	mustAct(t, &game, 2, Action{Type: Call})
+	if options, ok := game.ActionOptions(3); !ok || !options.CanCheck || !options.CanRaise {
+		t.Error("Straddle gets the option")
+	}
+
+	if _, ok := game.Straddler(); ok {
+		t.Error("Straddle only lasts one hand")
+	}
+}
+
+func TestButtonStraddle(t *testing.T) {
+	game := newTestGame(t, GameOptions{Blinds: Blinds{SmallBlind: 5, BigBlind: 10, Straddles: Straddles{Button: true}}}, 0, 1, 2, 3)
+	if err := game.Straddle(3); err == nil {
+		t.Error("Under the gun straddles are not allowed")
+	}
+	if err := game.Straddle(0); err != nil {
+		t.Fatal(err)
+	}
+	game.StartHand()
+
+	view, _ := game.HandView(-1)
+	if view.Seats[0].Bet != 20 || view.ToAct != 3 {
+		t.Error("Action starts left of the big blind")
+	}
+
+	mustAct(t, &game, 3, Action{Type: Call})
+	if game.hand.toAct != 1 {
+		t.Error("Action skips the button")
+	}
+	mustAct(t, &game, 1, Action{Type: Call})
+	mustAct(t, &game, 2, Action{Type: Call})
+	if game.hand.toAct != 0 {
+		t.Error("Button acts last")
+	}
+	mustAct(t, &game, 0, Action{Type: Check})
+
+	if game.hand.street != 1 || game.hand.toAct != 1 {
+		t.Error("Normal order after the flop")
+	}
+}
+
+func TestNoStraddleHeadsUp(t *testing.T) {
+	game := newTestGame(t, GameOptions{Blinds: Blinds{SmallBlind: 5, BigBlind: 10, Straddles: Straddles{UnderTheGun: true, Button: true}}}, 0, 1)
+	if game.Straddle(0) == nil || game.Straddle(1) == nil {
+		t.Error()
+	}
+}
@@ -0,0 +1,93 @@
+package poker
+
+import (
+	"fmt"
+)
+
+// BombPots are hands where everyone antes and there is no betting before the flop
+type BombPots struct {
+	// Ante is posted by every player in a bomb pot. Bomb pots are not allowed when it is 0
+	Ante int `json:"ante"`
+	// Every schedules a bomb pot every this many hands. 0 means they only happen by vote
+	Every int `json:"every"`
+}
+
+func (bombPots *BombPots) validate() error {
+	if bombPots.Ante < 0 || bombPots.Every < 0 {
+		return fmt.Errorf("Bomb pots cannot have a negative ante or schedule")
+	}
+	if bombPots.Every > 0 && bombPots.Ante == 0 {
+		return fmt.Errorf("Scheduled bomb pots need an ante")
+	}
+	return nil
+}
+
+// BombPots gets the bomb pot rules for the game
+func (game *Game) BombPots() BombPots {
+	return game.bombPots
+}
+
+// VoteBombPot has the player in seat vote for the next hand to be a bomb pot. It is a bomb
+// pot once everyone with chips has voted for it
+func (game *Game) VoteBombPot(seat int) error {
+	if game.bombPots.Ante == 0 {
+		return fmt.Errorf("Game does not allow bomb pots")
+	}
+	if game.HandInProgress() {
+		return fmt.Errorf("Can only vote for a bomb pot before the hand is dealt")
+	}
+	if player, ok := game.players[seat]; !ok || !player.dealtIn() {
+		return fmt.Errorf("Seat %d cannot play the next hand", seat)
+	}
+
+	game.bombPotVotes[seat] = true
+	return nil
+}
+
+// CancelBombPotVote takes back a vote for a bomb pot
+func (game *Game) CancelBombPotVote(seat int) error {
+	if !game.bombPotVotes[seat] {
+		return fmt.Errorf("Seat %d has not voted for a bomb pot", seat)
+	}
+
+	delete(game.bombPotVotes, seat)
+	return nil
+}
+
+// BombPotVotes gets the seats which have voted for the next hand to be a bomb pot
+func (game *Game) BombPotVotes() []int {
+	result := make([]int, 0, len(game.bombPotVotes))
+	for _, seat := range game.activeSeats() {
+		if game.bombPotVotes[seat] {
+			result = append(result, seat)
+		}
+	}
+	return result
+}
+
+// NextHandBombPot tells if the next hand will be a bomb pot, either because it is scheduled
+// or everyone voted for it. Stud games are never bomb pots since there is no flop
+func (game *Game) NextHandBombPot() bool {
+	if game.bombPots.Ante == 0 {
+		return false
+	}
+	if upcoming, ok := game.UpcomingGame(); ok && rulesFor(upcoming.Variant).stud {
+		return false
+	}
+
+	if every := game.bombPots.Every; every > 0 && (game.handsPlayed+1)%every == 0 {
+		return true
+	}
+
+	active := game.activeSeats()
+	return len(active) > 0 && len(game.BombPotVotes()) == len(active)
+}
+
+// postBombPot has everyone post the bomb pot ante, with no one left to act before the flop
+func (game *Game) postBombPot() {
+	hand := game.hand
+	for _, seat := range hand.seats {
+		game.postAnte(seat, game.bombPots.Ante)
+		hand.needsToAct[seat] = false
+	}
+}
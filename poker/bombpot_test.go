@@ -0,0 +1,56 @@
+package poker
+
+import (
+	"testing"
+)
+
+func TestVotedBombPot(t *testing.T) {
+	game := newTestGame(t, GameOptions{BombPots: BombPots{Ante: 10}}, 0, 1, 2)
+	game.VoteBombPot(0)
+	game.VoteBombPot(1)
+	if game.NextHandBombPot() {
+		t.Error("Everyone has to vote for a bomb pot")
+	}
+	game.VoteBombPot(2)
+	if !game.NextHandBombPot() {
+		t.Fatal()
+	}
+	game.StartHand()
+
+	view, _ := game.HandView(-1)
+	if !view.BombPot || view.Pot != 30 || view.Street != 1 || len(view.Board) != 3 || view.Seats[2].Bet != 0 {
+		t.Error("Everyone antes and goes straight to the flop", view)
+	}
+
+	for game.HandInProgress() {
+		mustAct(t, &game, game.hand.toAct, Action{Type: Fold})
+	}
+	if !game.History()[0].BombPot || game.NextHandBombPot() {
+		t.Error("Votes only last one hand")
+	}
+}
+
+func TestScheduledBombPot(t *testing.T) {
+	game := newTestGame(t, GameOptions{BombPots: BombPots{Ante: 10, Every: 2}}, 0, 1, 2)
+	foldHand(t, &game)
+	if !game.NextHandBombPot() {
+		t.Fatal()
+	}
+	foldHand(t, &game)
+
+	history := game.History()
+	if history[0].BombPot || !history[1].BombPot {
+		t.Error()
+	}
+}
+
+func TestBombPotsNotAllowed(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2)
+	if err := game.VoteBombPot(0); err == nil {
+		t.Error()
+	}
+
+	if _, err := NewGameWithOptions(GameOptions{StarterChips: 100, BlindSize: 10, BombPots: BombPots{Every: 3}}); err == nil {
+		t.Error("Scheduled bomb pots need an ante")
+	}
+}
@@ -1,5 +1,10 @@
 package poker
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Suit is the suit of a card
 type Suit int
 
@@ -38,6 +43,8 @@ const (
 	Queen
 	King
 	Ace
+	// Joker is not a natural card value, it is always wild when included in a deck
+	Joker
 )
 
 func (v CardValue) String() string {
@@ -55,6 +62,7 @@ func (v CardValue) String() string {
 		"Queen",
 		"King",
 		"Ace",
+		"Joker",
 	}[v-2]
 }
 
@@ -64,9 +72,59 @@ type Card struct {
 	value CardValue
 }
 
+// IsJoker tells if the card is a joker. The suit of a joker is only used to tell multiple
+// jokers in the same deck apart
+func (c Card) IsJoker() bool {
+	return c.value == Joker
+}
+
 const (
 	// NumCardValues is the number of possible card values
 	NumCardValues = 13
 	// NumSuits is the umber of possible card suits
 	NumSuits = 4
 )
+
+const cardValueChars = "23456789TJQKAX"
+const suitChars = "SHDC"
+
+// String gives the two character short hand for a card, i.e. "AS" for the ace of spades or
+// "XH" for a joker
+func (c Card) String() string {
+	return string(cardValueChars[c.value-Two]) + string(suitChars[c.suit])
+}
+
+// MarshalText serializes a card with its two character short hand
+func (c Card) MarshalText() ([]byte, error) {
+	if c.value < Two || c.value > Joker || c.suit < Spades || c.suit > Clubs {
+		return nil, fmt.Errorf("Cannot serialize invalid card")
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText parses a card from its two character short hand
+func (c *Card) UnmarshalText(text []byte) error {
+	if len(text) != 2 {
+		return fmt.Errorf("Invalid card %q", string(text))
+	}
+
+	value := strings.IndexByte(cardValueChars, text[0])
+	suit := strings.IndexByte(suitChars, text[1])
+	if value == -1 || suit == -1 {
+		return fmt.Errorf("Invalid card %q", string(text))
+	}
+
+	c.value = Two + CardValue(value)
+	c.suit = Suit(suit)
+	return nil
+}
+
+// Value gets the value of the card
+func (c Card) Value() CardValue {
+	return c.value
+}
+
+// Suit gets the suit of the card
+func (c Card) Suit() Suit {
+	return c.suit
+}
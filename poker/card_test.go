@@ -15,3 +15,29 @@ func TestCardString(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestJokerString(t *testing.T) {
+	if Joker.String() != "Joker" {
+		t.Error()
+	}
+
+	if !(Card{suit: Hearts, value: Joker}).IsJoker() || (Card{suit: Hearts, value: Ace}).IsJoker() {
+		t.Error()
+	}
+}
+
+func TestCardText(t *testing.T) {
+	card := Card{suit: Diamonds, value: Ten}
+	text, err := card.MarshalText()
+	if err != nil || string(text) != "TD" {
+		t.Error()
+	}
+
+	parsed := Card{}
+	if err := parsed.UnmarshalText([]byte("XH")); err != nil || !parsed.IsJoker() || parsed.Suit() != Hearts {
+		t.Error()
+	}
+	if err := parsed.UnmarshalText([]byte("1S")); err == nil {
+		t.Error()
+	}
+}
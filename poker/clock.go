@@ -0,0 +1,22 @@
+package poker
+
+import (
+	"time"
+)
+
+// Clock tells the time. Everything timed in a game goes through its clock, so timers can be
+// tested without waiting on them
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc lets a plain function be used as a Clock
+type ClockFunc func() time.Time
+
+// Now calls the function
+func (clock ClockFunc) Now() time.Time {
+	return clock()
+}
+
+// SystemClock is the clock on the wall, which games use unless they are given one
+var SystemClock Clock = ClockFunc(time.Now)
@@ -9,16 +9,49 @@ import (
 // NumCards in the deck
 const NumCards int = 52
 
+// MaxJokers is the most jokers which can be added to a deck, one per suit
+const MaxJokers int = NumSuits
+
+// DeckOptions are the house rules for which cards are in the deck, and which are wild
+type DeckOptions struct {
+	// Jokers is the number of jokers added to the deck. Jokers are always wild
+	Jokers int
+	// WildValues are card values which are wild in addition to any jokers, i.e. Two for deuces wild
+	WildValues []CardValue
+}
+
 // Deck keeps track of cards
 type Deck struct {
-	cards  []Card
-	dealt  []Card
-	random *rand.Rand
+	cards   []Card
+	dealt   []Card
+	random  *rand.Rand
+	options DeckOptions
 }
 
-// NewDeck creates a new Deck
+// NewDeck creates a new standard 52 card Deck, with no wild cards
 func NewDeck() Deck {
-	cards := make([]Card, 0, NumCards)
+	deck, err := NewDeckWithOptions(DeckOptions{})
+	if err != nil {
+		panic("Default deck options should always be valid")
+	}
+
+	return deck
+}
+
+// NewDeckWithOptions creates a new deck with the specified jokers and wild cards.
+// Returns an error if the options are invalid
+func NewDeckWithOptions(options DeckOptions) (Deck, error) {
+	if options.Jokers < 0 || options.Jokers > MaxJokers {
+		return Deck{}, fmt.Errorf("Deck can have between 0 and %d jokers, but got %d", MaxJokers, options.Jokers)
+	}
+
+	for _, value := range options.WildValues {
+		if value < Two || value > Ace {
+			return Deck{}, fmt.Errorf("Invalid wild card value %d", value)
+		}
+	}
+
+	cards := make([]Card, 0, NumCards+options.Jokers)
 
 	var suits = [...]Suit{
 		Spades,
@@ -53,18 +86,23 @@ func NewDeck() Deck {
 		panic("Did not construct correct number of cards")
 	}
 
+	for i := 0; i < options.Jokers; i++ {
+		cards = append(cards, Card{suit: suits[i], value: Joker})
+	}
+
 	seed := rand.NewSource(time.Now().UnixNano())
 	random := rand.New(seed)
 
-	deck := Deck{cards: cards, dealt: make([]Card, 0), random: random}
+	deck := Deck{cards: cards, dealt: make([]Card, 0), random: random, options: options}
 	deck.Shuffle()
-	return deck
+	return deck, nil
 }
 
 // Shuffle takes all the dealt and un-dealt cards and s
 func (deck *Deck) Shuffle() {
 	deck.cards = append(deck.cards, deck.dealt...)
-	if len(deck.cards) != NumCards {
+	deck.dealt = deck.dealt[:0]
+	if len(deck.cards) != deck.Size() {
 		panic("Deck somehow does not have correct nubmer of cards")
 	}
 
@@ -88,3 +126,18 @@ func (deck *Deck) DealCard() (Card, error) {
 func (deck *Deck) Len() int {
 	return len(deck.cards)
 }
+
+// Size returns the total number of cards in the deck, dealt or not
+func (deck *Deck) Size() int {
+	return NumCards + deck.options.Jokers
+}
+
+// Options returns the options the deck was built with
+func (deck *Deck) Options() DeckOptions {
+	return deck.options
+}
+
+// IsWild tells if a card is wild with this deck's options
+func (deck *Deck) IsWild(card Card) bool {
+	return isWild(card, deck.options.WildValues)
+}
@@ -49,3 +49,45 @@ func TestDealAndShuffle(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestDeckWithJokers(t *testing.T) {
+	deck, err := NewDeckWithOptions(DeckOptions{Jokers: 2, WildValues: []CardValue{Two}})
+	if err != nil {
+		t.Error(err)
+	}
+
+	cards := make(map[Card]int)
+	jokers := 0
+	wilds := 0
+	for deck.Len() > 0 {
+		c, _ := deck.DealCard()
+		cards[c]++
+		if c.IsJoker() {
+			jokers++
+		}
+		if deck.IsWild(c) {
+			wilds++
+		}
+	}
+
+	if len(cards) != NumCards+2 || jokers != 2 || wilds != 2+NumSuits {
+		t.Error()
+	}
+
+	deck.Shuffle()
+	if deck.Len() != NumCards+2 {
+		t.Error()
+	}
+}
+
+func TestDeckInvalidOptions(t *testing.T) {
+	if _, err := NewDeckWithOptions(DeckOptions{Jokers: -1}); err == nil {
+		t.Error()
+	}
+	if _, err := NewDeckWithOptions(DeckOptions{Jokers: MaxJokers + 1}); err == nil {
+		t.Error()
+	}
+	if _, err := NewDeckWithOptions(DeckOptions{WildValues: []CardValue{Joker}}); err == nil {
+		t.Error()
+	}
+}
@@ -0,0 +1,39 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors which callers may want to tell apart. The errors returned have their own messages,
+// and match these with errors.Is
+var (
+	ErrSeatTaken   = errors.New("Seat is taken")
+	ErrGameFull    = errors.New("Game is full")
+	ErrInvalidSeat = errors.New("Invalid seat")
+	ErrWrongClaim  = errors.New("Wrong claim for the reservation")
+	// ErrInvalidAction is an action the rules don't allow, like a raise of the wrong size
+	ErrInvalidAction = errors.New("Invalid action")
+
+	ErrSpectatingDisabled = errors.New("Spectating is disabled")
+	ErrNotSpectating      = errors.New("Not spectating")
+)
+
+// kindError is an error with a detailed message which still matches its kind
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (err *kindError) Error() string {
+	return err.message
+}
+
+func (err *kindError) Unwrap() error {
+	return err.kind
+}
+
+// errorOf formats an error which matches kind
+func errorOf(kind error, format string, args ...interface{}) error {
+	return &kindError{kind: kind, message: fmt.Sprintf(format, args...)}
+}
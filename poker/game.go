@@ -1,18 +1,35 @@
 package poker
 
 import (
+	"crypto/rand"
 	"fmt"
+	"math/big"
+	"time"
 )
 
-// NumSeats is the number of seats in a game
-const NumSeats int = 8
+// Table sizes run from heads up to 10-max, with games having 8 seats unless they pick a size
+const (
+	MinTableSize     int = 2
+	MaxTableSize     int = 10
+	DefaultTableSize int = 8
+)
 
 // Player is a registered participant in the game
 type Player struct {
 	Name   string `json:"name"`
 	Chips  int    `json:"chips"`
 	Seat   int    `json:"seat"`
-	secret int
+	BuyIns BuyIns `json:"buyIns"`
+	// SittingOut players keep their seat and chips, but are not dealt in
+	SittingOut bool `json:"sittingOut"`
+	// OwesBlind is set when the blinds passed a player while they were sitting out
+	OwesBlind bool `json:"owesBlind"`
+	// TimeBank is the extra time the player has left once their shot clock runs out
+	TimeBank time.Duration `json:"timeBank"`
+	timeouts int
+	secret   int
+	// id tells the player apart from anyone else who sat in the game, even with the same name
+	id int
 }
 
 // Secret is a getter for the player secret. Should ONLY be used to give access to
@@ -21,50 +38,237 @@ func (player *Player) Secret() int {
 	return player.secret
 }
 
-// Game is the highest level object, representing and entire poker game
+// maxSecret keeps secrets within the integers a JSON number holds exactly, since browsers
+// read them as floats
+const maxSecret = 1 << 53
+
+// newSecret makes a random secret for a player or entrant, which is what they prove who they
+// are with. It is positive, so it can't be mistaken for a seat or an unset secret
+func newSecret() int {
+	secret, err := rand.Int(rand.Reader, big.NewInt(maxSecret-1))
+	if err != nil {
+		panic(err)
+	}
+	return int(secret.Int64()) + 1
+}
+
+// Game is the highest level object, representing and entire poker game. A copy of a Game
+// shares its players and hand with the original, so changes to one would only partly show up
+// in the other. Once made, a Game should only be passed around by pointer
 type Game struct {
 	players      map[int]Player
+	tableSize    int
 	deck         Deck
 	starterChips int
-	blindSize    int
+	blinds       Blinds
 	button       int
+	straddler    int
+
+	limits      BettingLimits
+	hand        *handState
+	history     []HandRecord
+	rotation    Rotation
+	current     GameType
+	chosen      *GameType
+	handsPlayed int
+	handsInGame int
+	orbitSize   int
+
+	rebuys       RebuyRules
+	departed     []LedgerEntry
+	reservations map[int]Reservation
+	waitlist     []waitlistEntry
+	offerTimeout time.Duration
+	clock        Clock
+	shotClock    ShotClock
+	// refilledLevel is the tournament level the time banks were last refilled for
+	refilledLevel int
+	maxRuns       int
+	bombPots      BombPots
+	bombPotVotes  map[int]bool
+	rake          Rake
+	rakeCollected int
+	// spectators are when each spectator's token was last used to look at the game
+	spectators     map[string]time.Time
+	spectatorRules SpectatorRules
+	// seated is how many players have ever sat down, which numbers them
+	seated int
+}
+
+// GameOptions are the rules a game is created with
+type GameOptions struct {
+	StarterChips int
+	// TableSize is the number of seats, defaulting to DefaultTableSize
+	TableSize int
+	// BlindSize is the big blind, with the small blind being half. Ignored if Blinds are set
+	BlindSize int
+	Blinds    Blinds
+	Deck      DeckOptions
+	// Betting is the betting structure when there is no rotation of games
+	Betting  BettingStructure
+	Limits   BettingLimits
+	Rotation Rotation
+	// MaxRuns is the most times players all in can agree to run the rest of the board
+	MaxRuns    int
+	BombPots   BombPots
+	Rebuys     RebuyRules
+	ShotClock  ShotClock
+	Rake       Rake
+	Spectators SpectatorRules
+	// Clock is what the game tells time with, defaulting to SystemClock
+	Clock Clock
+	// SeatOfferTimeout is how long someone on the waitlist has to take an open seat, defaulting
+	// to DefaultSeatOfferTimeout
+	SeatOfferTimeout time.Duration
 }
 
 // NewGame creates a game with the specified rules
 func NewGame(starterChips int, blindSize int) (Game, error) {
-	if starterChips <= 0 {
+	return NewGameWithOptions(GameOptions{StarterChips: starterChips, BlindSize: blindSize})
+}
+
+// NewGameWithOptions creates a game with the specified rules, including any house rules
+// which are not part of a standard game
+func NewGameWithOptions(options GameOptions) (Game, error) {
+	if options.StarterChips <= 0 {
 		return Game{}, fmt.Errorf("Game needs to have a positive chip count")
 	}
-	if blindSize <= 0 || blindSize%2 != 0 {
-		return Game{}, fmt.Errorf("Game needs to have a positive blind size, which is divisible by 2")
+
+	tableSize := options.tableSize()
+	if tableSize < MinTableSize || tableSize > MaxTableSize {
+		return Game{}, fmt.Errorf("Game needs between %d and %d seats", MinTableSize, MaxTableSize)
+	}
+
+	blinds := options.Blinds
+	if blinds.SmallBlind == 0 && blinds.BigBlind == 0 {
+		if options.BlindSize <= 0 || options.BlindSize%2 != 0 {
+			return Game{}, fmt.Errorf("Game needs to have a positive blind size, which is divisible by 2")
+		}
+		blinds.SmallBlind = options.BlindSize / 2
+		blinds.BigBlind = options.BlindSize
+	}
+	if err := blinds.validate(); err != nil {
+		return Game{}, err
+	}
+
+	deck, err := NewDeckWithOptions(options.Deck)
+	if err != nil {
+		return Game{}, err
+	}
+
+	if err := options.Rotation.validate(); err != nil {
+		return Game{}, err
+	}
+
+	current := GameType{Variant: HoldEm, Betting: options.Betting}
+	if len(options.Rotation.Games) > 0 {
+		current = options.Rotation.Games[0]
+	}
+
+	limits := options.Limits
+	limits.fillDefaults(blinds.BigBlind)
+	spreadLimit := current.Betting == SpreadLimit
+	for _, gameType := range options.Rotation.Games {
+		spreadLimit = spreadLimit || gameType.Betting == SpreadLimit
+	}
+	if err := limits.validate(spreadLimit); err != nil {
+		return Game{}, err
+	}
+	if _, err := current.Betting.MarshalText(); err != nil {
+		return Game{}, err
+	}
+
+	if options.MaxRuns < 0 || options.MaxRuns > MaxBoardRuns {
+		return Game{}, fmt.Errorf("Can only run the board up to %d times", MaxBoardRuns)
+	}
+	if err := options.BombPots.validate(); err != nil {
+		return Game{}, err
+	}
+	if err := options.Rebuys.validate(); err != nil {
+		return Game{}, err
+	}
+	if err := options.Rake.validate(); err != nil {
+		return Game{}, err
+	}
+	if err := options.ShotClock.validate(); err != nil {
+		return Game{}, err
+	}
+	if err := options.Spectators.validate(); err != nil {
+		return Game{}, err
+	}
+	if options.Clock == nil {
+		options.Clock = SystemClock
+	}
+	if options.SeatOfferTimeout < 0 {
+		return Game{}, fmt.Errorf("Seat offers cannot have a negative timeout")
+	}
+	if options.SeatOfferTimeout == 0 {
+		options.SeatOfferTimeout = DefaultSeatOfferTimeout
 	}
 
 	return Game{
-		players:      make(map[int]Player),
-		deck:         NewDeck(),
-		starterChips: starterChips,
-		blindSize:    blindSize,
-		button:       -1,
+		players:        make(map[int]Player),
+		tableSize:      tableSize,
+		deck:           deck,
+		starterChips:   options.StarterChips,
+		blinds:         blinds,
+		button:         -1,
+		straddler:      -1,
+		limits:         limits,
+		rotation:       options.Rotation,
+		current:        current,
+		maxRuns:        options.MaxRuns,
+		bombPots:       options.BombPots,
+		bombPotVotes:   make(map[int]bool),
+		rebuys:         options.Rebuys,
+		reservations:   make(map[int]Reservation),
+		offerTimeout:   options.SeatOfferTimeout,
+		clock:          options.Clock,
+		shotClock:      options.ShotClock,
+		rake:           options.Rake,
+		spectators:     make(map[string]time.Time),
+		spectatorRules: options.Spectators,
 	}, nil
 }
 
+// tableSize gets the number of seats, filling in the default
+func (options *GameOptions) tableSize() int {
+	if options.TableSize == 0 {
+		return DefaultTableSize
+	}
+	return options.TableSize
+}
+
 // AddPlayer adds a new player to the game and generates some values for them
 // Returns an error for invalid arguments or the game being full
 func (game *Game) AddPlayer(name string, seat int) (Player, error) {
-	if len(game.players) >= NumSeats {
-		return Player{}, fmt.Errorf("Game already at capacity of %d players", NumSeats)
+	return game.ClaimSeat(name, seat, 0)
+}
+
+// ClaimSeat adds a new player to the game like AddPlayer, who can sit in a reserved seat with
+// the reservation's claim
+func (game *Game) ClaimSeat(name string, seat int, claim int) (Player, error) {
+	if len(game.players) >= game.tableSize {
+		return Player{}, errorOf(ErrGameFull, "Game already at capacity of %d players, join the waitlist instead", game.tableSize)
 	}
 
-	if seat < 0 || seat > 8 {
-		return Player{}, fmt.Errorf("Invalid seat number %d", seat)
+	if seat < 0 || seat >= game.tableSize {
+		return Player{}, errorOf(ErrInvalidSeat, "Invalid seat number %d", seat)
 	}
 
 	if _, ok := game.players[seat]; ok {
-		return Player{}, fmt.Errorf("Already have player at seat %d", seat)
+		return Player{}, errorOf(ErrSeatTaken, "Already have player at seat %d", seat)
+	}
+
+	if reservation, ok := game.reservation(seat); ok && reservation.claim != claim {
+		return Player{}, errorOf(ErrSeatTaken, "Seat %d is reserved", seat)
 	}
+	delete(game.reservations, seat)
 
-	secret := int(seat) * -1
-	player := Player{Name: name, Chips: game.starterChips, Seat: seat, secret: secret}
+	secret := newSecret()
+	buyIns := BuyIns{Initial: game.starterChips, Total: game.starterChips}
+	game.seated++
+	player := Player{Name: name, Chips: game.starterChips, Seat: seat, BuyIns: buyIns, TimeBank: game.shotClock.TimeBank, secret: secret, id: game.seated}
 	game.players[seat] = player
 	return player, nil
 }
@@ -74,11 +278,17 @@ func (game *Game) Players() map[int]Player {
 	return game.players
 }
 
-// EmptySeats returns a slice of the empty seats
+// TableSize gets the number of seats at the table
+func (game *Game) TableSize() int {
+	return game.tableSize
+}
+
+// EmptySeats returns a slice of the empty seats, which are not reserved
 func (game *Game) EmptySeats() []int {
-	result := make([]int, 0, NumSeats-len(game.players))
-	for i := 0; i < NumSeats; i++ {
-		if _, ok := game.players[i]; !ok {
+	result := make([]int, 0, game.tableSize-len(game.players))
+	for i := 0; i < game.tableSize; i++ {
+		_, reserved := game.reservation(i)
+		if _, ok := game.players[i]; !ok && !reserved {
 			result = append(result, i)
 		}
 	}
@@ -91,7 +301,210 @@ func (game *Game) StarterChips() int {
 	return game.starterChips
 }
 
-// BlindSize gets the blind size
+// BlindSize gets the blind size, which is the big blind
 func (game *Game) BlindSize() int {
-	return game.blindSize
+	return game.blinds.BigBlind
+}
+
+// DeckOptions gets the jokers and wild cards the game is played with
+func (game *Game) DeckOptions() DeckOptions {
+	return game.deck.Options()
+}
+
+// Button gets the seat of the dealer button, or -1 if no hand has been played
+func (game *Game) Button() int {
+	return game.button
+}
+
+// StartHand moves the button to the next player, picks the game for the hand from the
+// rotation and deals. Returns an error if the hand cannot be started
+func (game *Game) StartHand() error {
+	if game.HandInProgress() {
+		return fmt.Errorf("Hand %d is still in progress", game.handsPlayed)
+	}
+
+	if len(game.activeSeats()) < 2 {
+		return fmt.Errorf("Need at least 2 players with chips to start a hand")
+	}
+
+	bombPot := game.NextHandBombPot()
+	switching := game.switchDue()
+	if switching && game.rotation.DealersChoice {
+		if game.chosen == nil {
+			return fmt.Errorf("Waiting for seat %d to choose the next game", game.nextOccupiedSeat(game.button))
+		}
+		game.current = *game.chosen
+		game.chosen = nil
+	} else if switching {
+		game.current = game.nextInRotation()
+	}
+
+	if switching || game.handsPlayed == 0 {
+		game.handsInGame = 0
+		game.orbitSize = len(game.activeSeats())
+	}
+
+	game.button = game.nextOccupiedSeat(game.button)
+	game.handsInGame++
+	game.handsPlayed++
+	game.bombPotVotes = make(map[int]bool)
+	if !game.shotClock.RefillPerLevel {
+		game.refillTimeBanks()
+	}
+	game.deal(bombPot)
+	return nil
+}
+
+// CurrentGame gets the game being played in the current hand, or the first game if no hand
+// has been played
+func (game *Game) CurrentGame() GameType {
+	return game.current
+}
+
+// UpcomingGame gets the game which will be played next hand. Returns false if it is dealer's
+// choice and the dealer has not chosen yet
+func (game *Game) UpcomingGame() (GameType, bool) {
+	if !game.switchDue() {
+		return game.current, true
+	}
+
+	if game.rotation.DealersChoice {
+		if game.chosen == nil {
+			return GameType{}, false
+		}
+		return *game.chosen, true
+	}
+
+	return game.nextInRotation(), true
+}
+
+// HandsUntilNextGame gets the number of hands, including the next one, which will be played
+// before the game changes. Returns -1 if the game never changes
+func (game *Game) HandsUntilNextGame() int {
+	length := game.gameLength()
+	if length == 0 {
+		return -1
+	}
+
+	if game.switchDue() {
+		return game.nextGameLength()
+	}
+
+	return length - game.handsInGame
+}
+
+// Chooser gets the seat of the player who needs to choose the next game in dealer's choice.
+// Returns false if no choice needs to be made
+func (game *Game) Chooser() (int, bool) {
+	if !game.rotation.DealersChoice || !game.switchDue() || len(game.players) == 0 {
+		return -1, false
+	}
+
+	return game.nextOccupiedSeat(game.button), true
+}
+
+// ChooseGame is for dealer's choice, where the player about to get the button picks the
+// next game to be played
+func (game *Game) ChooseGame(seat int, gameType GameType) error {
+	chooser, ok := game.Chooser()
+	if !ok {
+		return fmt.Errorf("No game needs to be chosen right now")
+	}
+	if seat != chooser {
+		return fmt.Errorf("Seat %d is not the dealer, seat %d chooses the game", seat, chooser)
+	}
+
+	if _, err := gameType.Variant.MarshalText(); err != nil {
+		return err
+	}
+	if _, err := gameType.Betting.MarshalText(); err != nil {
+		return err
+	}
+	if !game.rotation.allows(gameType) {
+		return fmt.Errorf("Game %s is not part of the rotation", gameType)
+	}
+	if err := game.limits.validate(gameType.Betting == SpreadLimit); err != nil {
+		return err
+	}
+
+	game.chosen = &gameType
+	return nil
+}
+
+// Rotation gets the configured rotation of games
+func (game *Game) Rotation() Rotation {
+	return game.rotation
+}
+
+// gameLength is the number of hands the current game is played for, 0 if it is forever
+func (game *Game) gameLength() int {
+	return game.rotation.gameLength(game.orbitSize)
+}
+
+// nextGameLength is like gameLength, but for the game after the current one
+func (game *Game) nextGameLength() int {
+	return game.rotation.gameLength(len(game.activeSeats()))
+}
+
+// switchDue tells if the next hand will be a different game than the current one
+func (game *Game) switchDue() bool {
+	if game.handsPlayed == 0 {
+		return game.rotation.DealersChoice
+	}
+
+	length := game.gameLength()
+	return length > 0 && game.handsInGame >= length
+}
+
+// nextInRotation gets the game after the current one in the rotation order
+func (game *Game) nextInRotation() GameType {
+	games := game.rotation.Games
+	for i, gameType := range games {
+		if gameType == game.current {
+			return games[(i+1)%len(games)]
+		}
+	}
+	return game.current
+}
+
+// nextOccupiedSeat finds the first seat with a player who will be dealt in after the passed
+// in seat, wrapping around
+func (game *Game) nextOccupiedSeat(seat int) int {
+	for i := 1; i <= game.tableSize; i++ {
+		next := (seat + i + game.tableSize) % game.tableSize
+		if player, ok := game.players[next]; ok && player.dealtIn() {
+			return next
+		}
+	}
+	return -1
+}
+
+// activeSeats gets the seats of players who have chips to play with and aren't sitting out
+func (game *Game) activeSeats() []int {
+	result := make([]int, 0, len(game.players))
+	for i := 0; i < game.tableSize; i++ {
+		if player, ok := game.players[i]; ok && player.dealtIn() {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func (game *Game) adjustChips(seat int, amount int) {
+	player := game.players[seat]
+	player.Chips += amount
+	game.players[seat] = player
+}
+
+func (game *Game) smallBlind() int {
+	return game.blinds.SmallBlind
+}
+
+func (game *Game) bigBlind() int {
+	return game.blinds.BigBlind
+}
+
+// Limits gets the bet sizes used by the limit betting structures
+func (game *Game) Limits() BettingLimits {
+	return game.limits
 }
@@ -1,6 +1,7 @@
 package poker
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -16,3 +17,173 @@ func TestGameInvalidSeat(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestGameTableSize(t *testing.T) {
+	if _, err := NewGameWithOptions(GameOptions{StarterChips: 100, BlindSize: 10, TableSize: MaxTableSize + 1}); err == nil {
+		t.Error("Table is too big")
+	}
+
+	game := newTestGame(t, GameOptions{TableSize: 10}, 0, 9)
+	if game.TableSize() != 10 || len(game.EmptySeats()) != 8 {
+		t.Error(game.EmptySeats())
+	}
+
+	game = newTestGame(t, GameOptions{TableSize: 2}, 0, 1)
+	if _, err := game.AddPlayer("foo", 2); err == nil {
+		t.Error("Heads up table only has 2 seats")
+	}
+	if len(game.EmptySeats()) != 0 {
+		t.Error(game.EmptySeats())
+	}
+	game.StartHand()
+	view, _ := game.HandView(-1)
+	if view.Seats[0].Bet != 5 || view.Seats[1].Bet != 10 {
+		t.Error("Button posts the small blind heads up", view.Seats)
+	}
+}
+
+func TestGameWithWildDeck(t *testing.T) {
+	options := GameOptions{StarterChips: 100, BlindSize: 10, Deck: DeckOptions{Jokers: 2, WildValues: []CardValue{Two}}}
+	game, err := NewGameWithOptions(options)
+	if err != nil {
+		t.Error(err)
+	}
+	if game.DeckOptions().Jokers != 2 || game.deck.Size() != NumCards+2 {
+		t.Error()
+	}
+
+	options.Deck.Jokers = MaxJokers + 1
+	if _, err := NewGameWithOptions(options); err == nil {
+		t.Error()
+	}
+}
+
+func newTestGame(t *testing.T, options GameOptions, seats ...int) Game {
+	options.StarterChips = 100
+	options.BlindSize = 10
+	game, err := NewGameWithOptions(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, seat := range seats {
+		if _, err := game.AddPlayer("player", seat); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return game
+}
+
+// foldHand starts a hand and has everyone fold to the last player
+func foldHand(t *testing.T, game *Game) {
+	if err := game.StartHand(); err != nil {
+		t.Fatal(err)
+	}
+
+	for game.HandInProgress() {
+		if err := game.Act(game.hand.toAct, Action{Type: Fold}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGameRotationByHands(t *testing.T) {
+	game := newTestGame(t, GameOptions{Rotation: HORSE(2)}, 0, 3, 5)
+
+	expected := []Variant{HoldEm, HoldEm, OmahaHiLo, OmahaHiLo, Razz, Razz, Stud, Stud, StudHiLo, StudHiLo, HoldEm}
+	for i, variant := range expected {
+		upcoming, ok := game.UpcomingGame()
+		if !ok || upcoming.Variant != variant {
+			t.Errorf("Hand %d should announce %s, but got %s", i, variant, upcoming.Variant)
+		}
+		foldHand(t, &game)
+		if game.CurrentGame().Variant != variant {
+			t.Errorf("Hand %d should be %s, but got %s", i, variant, game.CurrentGame().Variant)
+		}
+	}
+}
+
+func TestGameRotationByOrbits(t *testing.T) {
+	rotation := Rotation{Games: []GameType{{Variant: HoldEm}, {Variant: Omaha, Betting: PotLimit}}, Orbits: 1}
+	game := newTestGame(t, GameOptions{Rotation: rotation}, 1, 2, 6)
+
+	for i := 0; i < 3; i++ {
+		foldHand(t, &game)
+		if game.CurrentGame().Variant != HoldEm {
+			t.Error()
+		}
+	}
+	if game.HandsUntilNextGame() != 3 {
+		t.Error(game.HandsUntilNextGame())
+	}
+
+	foldHand(t, &game)
+	if game.CurrentGame() != rotation.Games[1] || game.Button() != 1 {
+		t.Error()
+	}
+}
+
+func TestGameDealersChoice(t *testing.T) {
+	rotation := Rotation{Games: []GameType{{Variant: HoldEm}, {Variant: Razz, Betting: FixedLimit}}, DealersChoice: true}
+	game := newTestGame(t, GameOptions{Rotation: rotation}, 2, 4)
+
+	if err := game.StartHand(); err == nil {
+		t.Error("Dealer needs to choose a game first")
+	}
+
+	chooser, ok := game.Chooser()
+	if !ok || chooser != 2 {
+		t.Error()
+	}
+	if err := game.ChooseGame(4, rotation.Games[1]); err == nil {
+		t.Error("Only the dealer can choose")
+	}
+	if err := game.ChooseGame(2, GameType{Variant: Stud}); err == nil {
+		t.Error("Can only choose games in the rotation")
+	}
+	if err := game.ChooseGame(2, rotation.Games[1]); err != nil {
+		t.Error(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		foldHand(t, &game)
+		if game.CurrentGame() != rotation.Games[1] {
+			t.Error()
+		}
+	}
+
+	chooser, ok = game.Chooser()
+	if !ok || chooser != 2 {
+		t.Error("Once the orbit is over the next dealer chooses")
+	}
+	if _, ok := game.UpcomingGame(); ok {
+		t.Error()
+	}
+}
+
+func TestAddPlayerErrors(t *testing.T) {
+	game := newTestGame(t, GameOptions{TableSize: 2}, 0)
+	if _, err := game.AddPlayer("foo", 0); !errors.Is(err, ErrSeatTaken) {
+		t.Error(err)
+	}
+	if _, err := game.AddPlayer("foo", 2); !errors.Is(err, ErrInvalidSeat) || err.Error() != "Invalid seat number 2" {
+		t.Error(err)
+	}
+	game.AddPlayer("foo", 1)
+	if _, err := game.AddPlayer("foo", 1); !errors.Is(err, ErrGameFull) {
+		t.Error(err)
+	}
+}
+
+func TestPlayerSecrets(t *testing.T) {
+	game := newTestGame(t, GameOptions{TableSize: 10}, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	secrets := make(map[int]bool)
+	for seat, player := range game.Players() {
+		secret := player.Secret()
+		if secret <= 0 || secret >= maxSecret || secret == -seat || secrets[secret] {
+			t.Error("Secrets should be random", seat, secret)
+		}
+		secrets[secret] = true
+	}
+}
@@ -25,8 +25,18 @@ const (
 	fullHouseRank
 	fourKindRank
 	straightFlushRank
+	fiveKindRank
 )
 
+// FiveKind is only possible with wild cards, and has the five cards of the same value
+type FiveKind struct {
+	fivePair []Card
+}
+
+func (f FiveKind) rank() int32 {
+	return fiveKindRank
+}
+
 // StraightFlush has sorted cards, starting with the best card in the straight flush
 type StraightFlush struct {
 	sortedCards []Card
@@ -177,75 +187,214 @@ func findAllPairs(cards []Card) ([]Card, []Card, []Card) {
 		cardsByValue[card.value-2] = append(cardsByValue[card.value-2], card)
 	}
 
+	// The biggest groups make the best hand, and with equal sizes the highest value wins
+	groups := make([][]Card, 0)
 	for i := NumCardValues - 1; i >= 0; i-- {
-		if len(cardsByValue[i]) > 1 && len(high) == 0 {
-			high = cardsByValue[i]
-		} else if len(cardsByValue[i]) > 1 && len(low) == 0 {
-			low = cardsByValue[i]
-		} else {
-			kickers = append(kickers, cardsByValue[i]...)
+		if len(cardsByValue[i]) > 0 {
+			groups = append(groups, cardsByValue[i])
 		}
 	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i]) > len(groups[j])
+	})
 
-	if len(high) < len(low) {
-		return low, high, kickers
+	for _, group := range groups {
+		if len(group) > 1 && len(high) == 0 {
+			high = group
+		} else if len(group) > 1 && len(low) == 0 {
+			low = group
+		} else {
+			kickers = append(kickers, group...)
+		}
 	}
 
+	// Anything which didn't make the high or low pair is just a kicker, so only value matters
+	sort.SliceStable(kickers, func(i, j int) bool {
+		return kickers[i].value > kickers[j].value
+	})
+
 	return high, low, kickers
 }
 
 // SolveHand takes cards and builds the appropriate hand. Needs to be called with [5, 7] cards
-// If called with incorrect number of cards an error is returned
+// If called with incorrect number of cards an error is returned. Jokers are treated as wild
 func SolveHand(cards []Card) (Hand, error) {
+	return SolveWildHand(cards, nil)
+}
+
+// SolveWildHand is like SolveHand, but cards with a value in wildValues are wild along with
+// any jokers. Wild cards are substituted with whatever cards make the best possible hand
+func SolveWildHand(cards []Card, wildValues []CardValue) (Hand, error) {
 	if len(cards) < 5 || len(cards) > 7 {
 		return nil, fmt.Errorf("Only support hands with with size in [5, 7], but got %d cards", len(cards))
 	}
 
+	hasWild := false
+	for _, card := range cards {
+		hasWild = hasWild || isWild(card, wildValues)
+	}
+
+	if !hasWild {
+		return solveNaturalHand(cards), nil
+	}
+
+	// Wild cards make it hard to reason about which cards to use, so we just try every
+	// set of five cards and keep the best
+	var best Hand
+	for _, combination := range cardCombinations(cards, int(handSize)) {
+		hand := solveFiveWithWilds(combination, wildValues)
+		if best == nil || CompareHands(hand, best) < 0 {
+			best = hand
+		}
+	}
+
+	return best, nil
+}
+
+func isWild(card Card, wildValues []CardValue) bool {
+	if card.IsJoker() {
+		return true
+	}
+
+	for _, value := range wildValues {
+		if card.value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cardCombinations returns every way of choosing size cards, preserving order
+func cardCombinations(cards []Card, size int) [][]Card {
+	if size == 0 {
+		return [][]Card{{}}
+	}
+	if len(cards) < size {
+		return nil
+	}
+
+	result := make([][]Card, 0)
+	for _, rest := range cardCombinations(cards[1:], size-1) {
+		combination := append([]Card{cards[0]}, rest...)
+		result = append(result, combination)
+	}
+
+	return append(result, cardCombinations(cards[1:], size)...)
+}
+
+// solveFiveWithWilds solves exactly five cards, where some of them are wild. Since wild cards
+// are interchangeable, we only need to try each multiset of values for them. If the natural
+// cards all share a suit the wild cards take it, otherwise a flush is impossible anyways
+func solveFiveWithWilds(cards []Card, wildValues []CardValue) Hand {
+	naturals := make([]Card, 0, len(cards))
+	for _, card := range cards {
+		if !isWild(card, wildValues) {
+			naturals = append(naturals, card)
+		}
+	}
+	numWilds := len(cards) - len(naturals)
+
+	suit := Spades
+	fiveKindValue := Ace
+	if len(naturals) > 0 {
+		suit = naturals[0].suit
+		fiveKindValue = naturals[0].value
+	}
+	for _, card := range naturals {
+		if card.value != fiveKindValue {
+			fiveKindValue = -1
+		}
+	}
+
+	// Nothing beats five of a kind, so skip trying every substitution
+	if fiveKindValue != -1 {
+		fivePair := append([]Card{}, naturals...)
+		for len(fivePair) < int(handSize) {
+			fivePair = append(fivePair, Card{suit: suit, value: fiveKindValue})
+		}
+		return FiveKind{fivePair: fivePair}
+	}
+
+	var best Hand
+	substitutes := make([]CardValue, numWilds)
+	var substituteFrom func(index int, minValue CardValue)
+	substituteFrom = func(index int, minValue CardValue) {
+		if index == numWilds {
+			// Solving sorts the cards in place, so build them fresh each time
+			substituted := append(make([]Card, 0, len(cards)), naturals...)
+			for _, value := range substitutes {
+				substituted = append(substituted, Card{suit: suit, value: value})
+			}
+
+			hand := solveNaturalHand(substituted)
+			if best == nil || CompareHands(hand, best) < 0 {
+				best = hand
+			}
+			return
+		}
+
+		for value := minValue; value <= Ace; value++ {
+			substitutes[index] = value
+			substituteFrom(index+1, value)
+		}
+	}
+	substituteFrom(0, Two)
+
+	return best
+}
+
+// solveNaturalHand solves a hand where every card is taken at face value
+func solveNaturalHand(cards []Card) Hand {
 	highPairs, lowPairs, kickers := findAllPairs(cards)
 	straightFlush, flush := solveForStraightFlushOrFlush(cards)
 
+	if len(highPairs) == 5 {
+		return FiveKind{fivePair: highPairs}
+	}
+
 	if straightFlush != nil {
-		return *straightFlush, nil
+		return *straightFlush
 	}
 
 	if len(highPairs) == 4 {
 		if len(lowPairs) == 0 {
-			return FourKind{fourPair: highPairs, kicker: kickers[0]}, nil
+			return FourKind{fourPair: highPairs, kicker: kickers[0]}
 		} else if len(kickers) == 0 {
-			return FourKind{fourPair: highPairs, kicker: lowPairs[0]}, nil
+			return FourKind{fourPair: highPairs, kicker: lowPairs[0]}
 		} else if lowPairs[0].value < kickers[0].value {
-			return FourKind{fourPair: highPairs, kicker: kickers[0]}, nil
+			return FourKind{fourPair: highPairs, kicker: kickers[0]}
 		} else {
-			return FourKind{fourPair: highPairs, kicker: lowPairs[0]}, nil
+			return FourKind{fourPair: highPairs, kicker: lowPairs[0]}
 		}
 	}
 
-	if len(highPairs) == 3 && len(lowPairs) == 2 {
-		return FullHouse{threePair: highPairs, twoPair: lowPairs}, nil
+	if len(highPairs) == 3 && len(lowPairs) >= 2 {
+		return FullHouse{threePair: highPairs, twoPair: lowPairs[:2]}
 	}
 
 	if flush != nil {
-		return *flush, nil
+		return *flush
 	}
 
 	straight := solveForStraight(cards)
 	if straight != nil {
-		return *straight, nil
+		return *straight
 	}
 
 	if len(highPairs) == 3 {
-		return ThreeKind{threePair: highPairs, kickers: kickers[:2]}, nil
+		return ThreeKind{threePair: highPairs, kickers: kickers[:2]}
 	}
 
 	if len(highPairs) == 2 && len(lowPairs) == 2 {
-		return TwoPair{highPair: highPairs, lowPair: lowPairs, kicker: kickers[0]}, nil
+		return TwoPair{highPair: highPairs, lowPair: lowPairs, kicker: kickers[0]}
 	}
 
 	if len(highPairs) == 2 {
-		return Pair{pair: highPairs, kickers: kickers[:3]}, nil
+		return Pair{pair: highPairs, kickers: kickers[:3]}
 	}
 
-	return HighCard{sortedCards: kickers[:5]}, nil
+	return HighCard{sortedCards: kickers[:5]}
 }
 
 func kickerCompare(lhsKickers []Card, rhsKickers []Card) int {
@@ -272,6 +421,9 @@ func CompareHands(lhsHand Hand, rhsHand Hand) int {
 	}
 
 	switch lhs := lhsHand.(type) {
+	case FiveKind:
+		rhs := rhsHand.(FiveKind)
+		return int(rhs.fivePair[0].value - lhs.fivePair[0].value)
 	case StraightFlush:
 		rhs := rhsHand.(StraightFlush)
 		return int(rhs.sortedCards[0].value - lhs.sortedCards[0].value)
@@ -289,7 +441,7 @@ func CompareHands(lhsHand Hand, rhsHand Hand) int {
 		return int(rhs.twoPair[0].value - lhs.twoPair[0].value)
 	case Flush:
 		rhs := rhsHand.(Flush)
-		return int(rhs.sortedCards[0].value - lhs.sortedCards[0].value)
+		return kickerCompare(lhs.sortedCards, rhs.sortedCards)
 	case Straight:
 		rhs := rhsHand.(Straight)
 		return int(rhs.sortedCards[0].value - lhs.sortedCards[0].value)
@@ -321,3 +473,110 @@ func CompareHands(lhsHand Hand, rhsHand Hand) int {
 		panic("Got a Hand which did not match any expected types")
 	}
 }
+
+// LowHand is an ace to five low hand, used in Razz and the low half of hi/lo games. Aces are
+// low, and straights and flushes do not count against the hand
+type LowHand struct {
+	sortedCards []Card
+	// key orders low hands, where the lowest key wins. The first element is how paired the hand
+	// is, followed by the values with the most important first
+	key []int
+}
+
+// Qualifies tells if the low hand has no pairs and every card is at most maxValue, i.e. Eight
+// for an eight or better low
+func (l LowHand) Qualifies(maxValue CardValue) bool {
+	return l.key[0] == 0 && l.key[1] <= lowValue(maxValue)
+}
+
+func lowValue(value CardValue) int {
+	if value == Ace {
+		return 1
+	}
+	return int(value)
+}
+
+// lowHandKey builds the key for exactly five cards. Wild cards become the lowest value which
+// does not pair the hand
+func lowHandKey(cards []Card, wildValues []CardValue) []int {
+	counts := make(map[int]int)
+	numWilds := 0
+	for _, card := range cards {
+		if isWild(card, wildValues) {
+			numWilds++
+		} else {
+			counts[lowValue(card.value)]++
+		}
+	}
+	for value := 1; numWilds > 0 && value <= int(King); value++ {
+		if counts[value] == 0 {
+			counts[value]++
+			numWilds--
+		}
+	}
+
+	values := make([]int, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] > values[j]
+	})
+
+	// More cards sharing a value makes a worse low, this gives us no pair, pair, two pair, three
+	// of a kind, full house then four of a kind
+	paired := 0
+	switch {
+	case counts[values[0]] == 4:
+		paired = 5
+	case counts[values[0]] == 3 && counts[values[1]] == 2:
+		paired = 4
+	case counts[values[0]] == 3:
+		paired = 3
+	case counts[values[0]] == 2 && counts[values[1]] == 2:
+		paired = 2
+	case counts[values[0]] == 2:
+		paired = 1
+	}
+
+	return append([]int{paired}, values...)
+}
+
+func compareLowKeys(lhs []int, rhs []int) int {
+	for i := 0; i < len(lhs) && i < len(rhs); i++ {
+		if lhs[i] != rhs[i] {
+			return lhs[i] - rhs[i]
+		}
+	}
+	return len(lhs) - len(rhs)
+}
+
+// SolveLowHand finds the best ace to five low hand from [5, 7] cards. Jokers and cards with
+// a value in wildValues are wild
+func SolveLowHand(cards []Card, wildValues []CardValue) (LowHand, error) {
+	if len(cards) < 5 || len(cards) > 7 {
+		return LowHand{}, fmt.Errorf("Only support hands with with size in [5, 7], but got %d cards", len(cards))
+	}
+
+	var best LowHand
+	for _, combination := range cardCombinations(cards, int(handSize)) {
+		key := lowHandKey(combination, wildValues)
+		if best.key == nil || compareLowKeys(key, best.key) < 0 {
+			best = LowHand{sortedCards: combination, key: key}
+		}
+	}
+
+	sort.Slice(best.sortedCards, func(i, j int) bool {
+		return lowValue(best.sortedCards[i].value) > lowValue(best.sortedCards[j].value)
+	})
+	return best, nil
+}
+
+// CompareLowHands determines which low hand wins. Returns a negative number if lhs wins,
+// positive if rhs wins, and 0 if they chop
+func CompareLowHands(lhs LowHand, rhs LowHand) int {
+	return compareLowKeys(lhs.key, rhs.key)
+}
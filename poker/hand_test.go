@@ -40,6 +40,8 @@ func buildCards(cards []string) []Card {
 			value = King
 		case 'A':
 			value = Ace
+		case 'X':
+			value = Joker
 		default:
 			panic("Unknown card value")
 		}
@@ -445,3 +447,128 @@ func TestCompareHighCard(t *testing.T) {
 		t.Error()
 	}
 }
+
+func TestFiveKindDeucesWild(t *testing.T) {
+	cards := buildCards([]string{"2H", "2C", "AS", "AD", "AH", "KS", "3C"})
+	hand, _ := SolveWildHand(cards, []CardValue{Two})
+	fiveKind := hand.(FiveKind)
+
+	if fiveKind.rank() != fiveKindRank {
+		t.Error()
+	}
+	if !cardsMakePair(fiveKind.fivePair, Ace, 5) {
+		t.Error()
+	}
+}
+
+func TestJokerStraightFlush(t *testing.T) {
+	cards := buildCards([]string{"JH", "QH", "KH", "AH", "XS", "2C", "3D"})
+	hand, _ := SolveHand(cards)
+	straightFlush := hand.(StraightFlush)
+
+	if straightFlush.sortedCards[0].value != Ace {
+		t.Error("Joker should fill in the ten for a royal flush")
+	}
+}
+
+func TestJokerMakesBestHand(t *testing.T) {
+	cards := buildCards([]string{"9C", "9D", "4S", "7H", "XH"})
+	hand, _ := SolveHand(cards)
+	threeKind := hand.(ThreeKind)
+
+	if !cardsMakePair(threeKind.threePair, Nine, 3) {
+		t.Error()
+	}
+
+	cards = buildCards([]string{"8C", "9D", "JS", "2H", "XH", "XS"})
+	hand, _ = SolveHand(cards)
+	straight := hand.(Straight)
+
+	if straight.sortedCards[0].value != Queen {
+		t.Error("Jokers should make the highest possible straight")
+	}
+}
+
+func TestJokerFlush(t *testing.T) {
+	cards := buildCards([]string{"3C", "8C", "9C", "JC", "XH", "KD", "KH"})
+	hand, _ := SolveHand(cards)
+	flush := hand.(Flush)
+
+	expected := buildCards([]string{"AC", "JC", "9C", "8C", "3C"})
+	if !reflect.DeepEqual(flush.sortedCards, expected) {
+		t.Error()
+	}
+}
+
+func TestCompareFiveKind(t *testing.T) {
+	fiveKind, _ := SolveWildHand(buildCards([]string{"2H", "3C", "3S", "3D", "3H"}), []CardValue{Two})
+	straightFlush, _ := SolveHand(buildCards([]string{"9H", "TH", "JH", "QH", "KH"}))
+	betterFiveKind, _ := SolveHand(buildCards([]string{"XH", "4C", "4S", "4D", "4H"}))
+
+	if CompareHands(fiveKind, straightFlush) >= 0 || CompareHands(fiveKind, betterFiveKind) <= 0 {
+		t.Error()
+	}
+}
+
+func TestCompareFlushKickers(t *testing.T) {
+	h1, _ := SolveHand(buildCards([]string{"4H", "5H", "6H", "8H", "AH"}))
+	h2, _ := SolveHand(buildCards([]string{"4H", "5H", "7H", "8H", "AH"}))
+
+	if CompareHands(h1, h2) <= 0 {
+		t.Error()
+	}
+}
+
+func TestFullHouseFromTwoTrips(t *testing.T) {
+	cards := buildCards([]string{"4C", "4H", "4S", "9H", "9S", "9D", "KC"})
+	hand, _ := SolveHand(cards)
+	fullHouse := hand.(FullHouse)
+
+	if !cardsMakePair(fullHouse.threePair, Nine, 3) || !cardsMakePair(fullHouse.twoPair, Four, 2) {
+		t.Error()
+	}
+
+	cards = buildCards([]string{"AC", "AH", "KS", "KH", "5S", "5D", "5C"})
+	hand, _ = SolveHand(cards)
+	fullHouse = hand.(FullHouse)
+
+	if !cardsMakePair(fullHouse.threePair, Five, 3) || !cardsMakePair(fullHouse.twoPair, Ace, 2) {
+		t.Error()
+	}
+}
+
+func TestTwoPairFromThreePairs(t *testing.T) {
+	cards := buildCards([]string{"4C", "4H", "8S", "8H", "QS", "QD", "2C"})
+	hand, _ := SolveHand(cards)
+	twoPair := hand.(TwoPair)
+
+	if !cardsMakePair(twoPair.highPair, Queen, 2) || !cardsMakePair(twoPair.lowPair, Eight, 2) {
+		t.Error()
+	}
+	if twoPair.kicker.value != Four {
+		t.Error()
+	}
+}
+
+func TestLowHand(t *testing.T) {
+	wheel, _ := SolveLowHand(buildCards([]string{"AS", "2S", "3S", "4S", "5S", "KD", "KH"}), nil)
+	sixLow, _ := SolveLowHand(buildCards([]string{"AS", "2D", "3H", "4C", "6S", "6D", "QH"}), nil)
+	paired, _ := SolveLowHand(buildCards([]string{"AS", "AD", "3H", "3C", "6S", "6D", "QH"}), nil)
+
+	if CompareLowHands(wheel, sixLow) >= 0 || CompareLowHands(sixLow, paired) >= 0 {
+		t.Error()
+	}
+	if !wheel.Qualifies(Eight) || !sixLow.Qualifies(Six) || paired.Qualifies(Eight) {
+		t.Error()
+	}
+
+	expected := buildCards([]string{"6S", "4C", "3H", "2D", "AS"})
+	if !reflect.DeepEqual(sixLow.sortedCards, expected) {
+		t.Error(sixLow.sortedCards)
+	}
+
+	wild, _ := SolveLowHand(buildCards([]string{"XS", "2D", "3H", "8C", "6S"}), nil)
+	if !reflect.DeepEqual(wild.key, []int{0, 8, 6, 3, 2, 1}) {
+		t.Error("Joker should become an ace for the low")
+	}
+}
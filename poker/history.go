@@ -0,0 +1,98 @@
+package poker
+
+// HandRecord is the history of a hand which has finished
+type HandRecord struct {
+	Number int      `json:"number"`
+	Game   GameType `json:"game"`
+	Button int      `json:"button"`
+	Board  []Card   `json:"board"`
+	// Boards are every board when players ran it more than once
+	Boards  [][]Card       `json:"boards,omitempty"`
+	BombPot bool           `json:"bombPot"`
+	Actions []ActionRecord `json:"actions"`
+	// Shown are the hidden cards of every player who made it to showdown
+	Shown map[int][]Card `json:"shown"`
+	Pots  []PotResult    `json:"pots"`
+	// Rake is what the house took out of the pots
+	Rake int `json:"rake"`
+}
+
+// ActionRecord is a single action taken in a hand, including forced bets
+type ActionRecord struct {
+	Seat   int    `json:"seat"`
+	Street int    `json:"street"`
+	Action Action `json:"action"`
+}
+
+// PotResult is how a main or side pot was split up. When the board is run more than once
+// there is a result for each run of each pot
+type PotResult struct {
+	// Amount is the size of the pot, and Rake is what the house took out of it before it was split
+	Amount   int         `json:"amount"`
+	Rake     int         `json:"rake"`
+	Eligible []int       `json:"eligible"`
+	Run      int         `json:"run"`
+	Winnings map[int]int `json:"winnings"`
+}
+
+// History gets the records of every hand which has finished, oldest first
+func (game *Game) History() []HandRecord {
+	return game.history
+}
+
+// Pot is the total of every pot in the hand, before the rake
+func (record *HandRecord) Pot() int {
+	pot := 0
+	for _, result := range record.Pots {
+		pot += result.Amount
+	}
+	return pot
+}
+
+// SawFlop is how many players were still in the hand after the first round of betting, or 0
+// if everyone but one player folded before then. For stud games the "flop" is fourth street
+func (record *HandRecord) SawFlop() int {
+	dealtIn := make(map[int]bool)
+	for _, action := range record.Actions {
+		if action.Street == 0 {
+			dealtIn[action.Seat] = true
+		}
+	}
+	for _, action := range record.Actions {
+		if action.Street == 0 && action.Action.Type == Fold {
+			delete(dealtIn, action.Seat)
+		}
+	}
+
+	if len(dealtIn) < 2 {
+		return 0
+	}
+	return len(dealtIn)
+}
+
+// TableStats are averages over every hand played in a game, so players can tell what kind of
+// game it is before joining
+type TableStats struct {
+	Hands      int `json:"hands"`
+	AveragePot int `json:"averagePot"`
+	// PlayersPerFlop is how many players see the flop on average, counting hands which were won
+	// before the flop as no players
+	PlayersPerFlop float64 `json:"playersPerFlop"`
+}
+
+// Stats works out the game's TableStats from its history
+func (game *Game) Stats() TableStats {
+	stats := TableStats{Hands: len(game.history)}
+	if stats.Hands == 0 {
+		return stats
+	}
+
+	pots, sawFlop := 0, 0
+	for i := range game.history {
+		pots += game.history[i].Pot()
+		sawFlop += game.history[i].SawFlop()
+	}
+	stats.AveragePot = pots / stats.Hands
+	stats.PlayersPerFlop = float64(sawFlop) / float64(stats.Hands)
+	return stats
+}
@@ -0,0 +1,26 @@
+package poker
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2)
+	if stats := game.Stats(); stats.Hands != 0 || stats.AveragePot != 0 {
+		t.Error(stats)
+	}
+
+	foldHand(t, &game)
+	record := game.History()[0]
+	if record.Pot() != 15 || record.SawFlop() != 0 {
+		t.Error("Everyone folded to the big blind", record.Pot(), record.SawFlop())
+	}
+
+	checkDownHand(t, &game)
+	record = game.History()[1]
+	if record.Pot() != 30 || record.SawFlop() != 3 {
+		t.Error("Everyone limped in", record.Pot(), record.SawFlop())
+	}
+
+	if stats := game.Stats(); stats.Hands != 2 || stats.AveragePot != 22 || stats.PlayersPerFlop != 1.5 {
+		t.Error(stats)
+	}
+}
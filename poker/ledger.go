@@ -0,0 +1,204 @@
+package poker
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// LedgerEntry is what a player has put into and taken out of a cash game session
+type LedgerEntry struct {
+	Seat int    `json:"seat"`
+	Name string `json:"name"`
+	// Player numbers everyone who sat down in the game, so players who had the same seat and
+	// name are still told apart
+	Player int `json:"player"`
+	// BuyIn is what the player first sat down with, and TopUps are everything they bought after
+	BuyIn  int `json:"buyIn"`
+	TopUps int `json:"topUps"`
+	// CashOut is the player's stack. Chips in the pot of a hand in progress still count as theirs
+	CashOut int `json:"cashOut"`
+	// Net is how much the player is up, or down when negative
+	Net int `json:"net"`
+	// Left is set for players who have left the table, who cashed out with their stack then
+	Left bool `json:"left"`
+}
+
+// Transfer is a payment from one player to another to settle up
+type Transfer struct {
+	FromSeat   int    `json:"fromSeat"`
+	FromName   string `json:"fromName"`
+	FromPlayer int    `json:"fromPlayer"`
+	ToSeat     int    `json:"toSeat"`
+	ToName     string `json:"toName"`
+	ToPlayer   int    `json:"toPlayer"`
+	Amount     int    `json:"amount"`
+}
+
+// Ledger gets what every player has bought in for and would cash out with, ordered by seat,
+// followed by the players who have left in the order they left
+func (game *Game) Ledger() []LedgerEntry {
+	result := make([]LedgerEntry, 0, len(game.players)+len(game.departed))
+	for i := 0; i < game.tableSize; i++ {
+		player, ok := game.players[i]
+		if !ok {
+			continue
+		}
+
+		cashOut := player.Chips
+		if game.HandInProgress() {
+			cashOut += game.hand.contributed[i]
+		}
+		result = append(result, newLedgerEntry(i, player, cashOut))
+	}
+
+	return append(result, game.departed...)
+}
+
+func newLedgerEntry(seat int, player Player, cashOut int) LedgerEntry {
+	return LedgerEntry{
+		Seat:    seat,
+		Name:    player.Name,
+		Player:  player.id,
+		BuyIn:   player.BuyIns.Initial,
+		TopUps:  player.BuyIns.Total - player.BuyIns.Initial,
+		CashOut: cashOut,
+		Net:     cashOut - player.BuyIns.Total,
+	}
+}
+
+// HouseSeat is the seat the house is given in transfers, since it doesn't sit at the table
+const HouseSeat = -1
+
+// Settle works out who pays whom to settle up the game's ledger. The house is owed the rake
+// like a player who won it, so the nets add up to 0 and everything lost is paid to someone
+func (game *Game) Settle() []Transfer {
+	entries := game.Ledger()
+	if game.rakeCollected > 0 {
+		entries = append(entries, LedgerEntry{Seat: HouseSeat, Name: "House", CashOut: game.rakeCollected, Net: game.rakeCollected})
+	}
+	return Settle(entries)
+}
+
+// maxExactSettle is the most players owing or owed that Settle finds the fewest transfers for.
+// That means trying every group of them, which is too much work past a home game
+const maxExactSettle = 16
+
+// Settle works out who pays whom so everyone ends up with their net, in as few transfers as
+// possible. Players are split into as many groups that settle among themselves as there can be,
+// since a group of k players takes k-1 transfers. With more than maxExactSettle players to
+// settle it skips finding the groups, which still takes at most one less transfer than there
+// are players. If the nets don't add up to 0, whatever can't be matched is left unpaid
+func Settle(entries []LedgerEntry) []Transfer {
+	unsettled := make([]LedgerEntry, 0)
+	for _, entry := range entries {
+		if entry.Net != 0 {
+			unsettled = append(unsettled, entry)
+		}
+	}
+	if len(unsettled) > maxExactSettle {
+		return settleGroup(unsettled)
+	}
+
+	result := make([]Transfer, 0)
+	for _, group := range zeroSumGroups(unsettled) {
+		result = append(result, settleGroup(group)...)
+	}
+	return result
+}
+
+// zeroSumGroups splits entries into the most groups whose nets add up to 0. When the nets
+// don't add up to 0 overall, the first group is the one left over
+func zeroSumGroups(entries []LedgerEntry) [][]LedgerEntry {
+	// Subsets of entries are bit masks. groups is the most zero sum groups each subset splits into
+	full := 1<<uint(len(entries)) - 1
+	sums := make([]int, full+1)
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		lowest := bits.TrailingZeros(uint(mask))
+		sums[mask] = sums[mask&(mask-1)] + entries[lowest].Net
+		for i := range entries {
+			if mask&(1<<uint(i)) != 0 && groups[mask^1<<uint(i)] > groups[mask] {
+				groups[mask] = groups[mask^1<<uint(i)]
+			}
+		}
+		if sums[mask] == 0 {
+			groups[mask]++
+		}
+	}
+
+	// Taking entries out one at a time along the best split, a group ends whenever what's left
+	// adds up to 0
+	result := make([][]LedgerEntry, 0)
+	group := make([]LedgerEntry, 0)
+	for mask := full; mask != 0; {
+		for i := range entries {
+			rest := mask ^ 1<<uint(i)
+			if mask&(1<<uint(i)) == 0 {
+				continue
+			}
+			if sums[mask] == 0 && groups[rest]+1 != groups[mask] || sums[mask] != 0 && groups[rest] != groups[mask] {
+				continue
+			}
+
+			group = append(group, entries[i])
+			mask = rest
+			if sums[mask] == 0 {
+				result = append(result, group)
+				group = make([]LedgerEntry, 0)
+			}
+			break
+		}
+	}
+	return result
+}
+
+// settleGroup has the biggest loser pay the biggest winner until one of them is square, which
+// takes at most one less transfer than there are entries
+func settleGroup(entries []LedgerEntry) []Transfer {
+	owes := make([]LedgerEntry, 0)
+	owed := make([]LedgerEntry, 0)
+	for _, entry := range entries {
+		if entry.Net < 0 {
+			entry.Net = -entry.Net
+			owes = append(owes, entry)
+		} else if entry.Net > 0 {
+			owed = append(owed, entry)
+		}
+	}
+
+	byNet := func(entries []LedgerEntry) {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Net > entries[j].Net
+		})
+	}
+	byNet(owes)
+	byNet(owed)
+
+	result := make([]Transfer, 0)
+	for len(owes) > 0 && len(owed) > 0 {
+		from, to := &owes[0], &owed[0]
+		amount := minInt(from.Net, to.Net)
+		result = append(result, Transfer{
+			FromSeat:   from.Seat,
+			FromName:   from.Name,
+			FromPlayer: from.Player,
+			ToSeat:     to.Seat,
+			ToName:     to.Name,
+			ToPlayer:   to.Player,
+			Amount:     amount,
+		})
+
+		from.Net -= amount
+		to.Net -= amount
+		if from.Net == 0 {
+			owes = owes[1:]
+		}
+		if to.Net == 0 {
+			owed = owed[1:]
+		}
+		byNet(owes)
+		byNet(owed)
+	}
+
+	return result
+}
@@ -0,0 +1,75 @@
+package poker
+
+import (
+	"testing"
+)
+
+func TestLedger(t *testing.T) {
+	game := newTestGame(t, GameOptions{Rebuys: RebuyRules{MaxRebuys: Unlimited, Threshold: 50}}, 0, 1, 2)
+	setChips(&game, 0, 50)
+	game.Rebuy(0)
+	setChips(&game, 1, 200)
+
+	ledger := game.Ledger()
+	if len(ledger) != 3 || ledger[0].BuyIn != 100 || ledger[0].TopUps != 100 || ledger[0].CashOut != 150 || ledger[0].Net != -50 {
+		t.Error(ledger)
+	}
+	if ledger[1].Net != 100 {
+		t.Error(ledger[1])
+	}
+
+	game.StartHand()
+	if ledger := game.Ledger(); ledger[1].CashOut != 200 || ledger[2].CashOut != 100 {
+		t.Error("Chips in the pot still count", ledger)
+	}
+}
+
+func TestSettle(t *testing.T) {
+	transfers := Settle([]LedgerEntry{
+		{Seat: 0, Name: "a", Net: -50},
+		{Seat: 1, Name: "b", Net: 80},
+		{Seat: 2, Name: "c", Net: -40},
+		{Seat: 3, Name: "d", Net: 10},
+		{Seat: 4, Name: "e", Net: 0},
+	})
+
+	if len(transfers) != 3 {
+		t.Fatal(transfers)
+	}
+	if transfers[0].FromSeat != 0 || transfers[0].ToSeat != 1 || transfers[0].Amount != 50 {
+		t.Error(transfers[0])
+	}
+	if transfers[1].FromSeat != 2 || transfers[1].ToSeat != 1 || transfers[1].Amount != 30 {
+		t.Error(transfers[1])
+	}
+	if transfers[2].FromSeat != 2 || transfers[2].ToSeat != 3 || transfers[2].Amount != 10 {
+		t.Error(transfers[2])
+	}
+}
+
+func TestSettleFewestTransfers(t *testing.T) {
+	// Paying the biggest winner first takes 4 transfers, when c paying b squares them both
+	transfers := Settle([]LedgerEntry{
+		{Seat: 0, Name: "a", Net: 30},
+		{Seat: 1, Name: "b", Net: 40},
+		{Seat: 2, Name: "c", Net: -40},
+		{Seat: 3, Name: "d", Net: 20},
+		{Seat: 4, Name: "e", Net: -50},
+	})
+
+	if len(transfers) != 3 {
+		t.Fatal(transfers)
+	}
+	paid := map[int]int{}
+	for _, transfer := range transfers {
+		paid[transfer.FromSeat] -= transfer.Amount
+		paid[transfer.ToSeat] += transfer.Amount
+	}
+	if paid[0] != 30 || paid[1] != 40 || paid[2] != -40 || paid[3] != 20 || paid[4] != -50 {
+		t.Error("Everyone ends up with their net", transfers)
+	}
+
+	if transfers := Settle([]LedgerEntry{{Seat: 0, Net: -50}, {Seat: 1, Net: 30}}); len(transfers) != 1 || transfers[0].Amount != 30 {
+		t.Error("What doesn't add up is left unpaid", transfers)
+	}
+}
@@ -0,0 +1,501 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Entrant is a player registered in a multi-table tournament
+type Entrant struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Table and Seat are where the entrant is sitting, with Table -1 before the tournament starts,
+	// after they are knocked out and while they wait for a seat to re-enter at
+	Table  int    `json:"table"`
+	Seat   int    `json:"seat"`
+	BuyIns BuyIns `json:"buyIns"`
+	secret int
+}
+
+// Secret is a getter for the entrant secret. Should ONLY be used to give access to
+// a user who actually is this entrant.
+func (entrant *Entrant) Secret() int {
+	return entrant.secret
+}
+
+// TableMove is a player being moved to another table to keep the tables balanced
+type TableMove struct {
+	Entrant   int `json:"entrant"`
+	FromTable int `json:"fromTable"`
+	FromSeat  int `json:"fromSeat"`
+	ToTable   int `json:"toTable"`
+	ToSeat    int `json:"toSeat"`
+	// Broken is set when the move is because the table the player was at was broken up
+	Broken bool `json:"broken"`
+}
+
+// MultiTableTournament is a tournament with more players than fit at one table. Players are
+// moved between tables to keep them within one player of each other, and tables are broken
+// up as players are knocked out until everyone left is at the final table
+type MultiTableTournament struct {
+	options  TournamentOptions
+	schedule blindSchedule
+	tables   map[int]*Game
+	entrants []Entrant
+	finishes []Finish
+	moves    []TableMove
+
+	startingChips map[int]int
+	lastHand      map[int]int
+	// reEntries are the entrants who re-entered, waiting for a table to finish its hand
+	reEntries []int
+}
+
+// NewMultiTableTournament creates a multi-table tournament which players can register for
+func NewMultiTableTournament(options TournamentOptions) (MultiTableTournament, error) {
+	game, err := options.newTable()
+	if err != nil {
+		return MultiTableTournament{}, err
+	}
+
+	return MultiTableTournament{
+		options:       options,
+		schedule:      blindSchedule{levels: options.Levels, clock: game.clock},
+		tables:        make(map[int]*Game),
+		startingChips: make(map[int]int),
+		lastHand:      make(map[int]int),
+	}, nil
+}
+
+// Register adds a new entrant to the tournament. Entrants can only register before it starts
+func (tournament *MultiTableTournament) Register(name string) (Entrant, error) {
+	if tournament.Started() {
+		return Entrant{}, fmt.Errorf("Tournament has already started")
+	}
+
+	entrant := Entrant{
+		ID:     len(tournament.entrants),
+		Name:   name,
+		Table:  -1,
+		Seat:   -1,
+		BuyIns: BuyIns{Initial: tournament.options.BuyIn, Total: tournament.options.BuyIn},
+		secret: newSecret(),
+	}
+	tournament.entrants = append(tournament.entrants, entrant)
+	return entrant, nil
+}
+
+// Start seats the entrants at as few tables as they fit at, spread out evenly
+func (tournament *MultiTableTournament) Start() error {
+	if tournament.Started() {
+		return fmt.Errorf("Tournament has already started")
+	}
+	if len(tournament.entrants) < 2 {
+		return fmt.Errorf("Need at least 2 entrants to start a tournament")
+	}
+
+	numTables := (len(tournament.entrants) + tournament.tableSize() - 1) / tournament.tableSize()
+	for i := 0; i < numTables; i++ {
+		table, err := tournament.options.newTable()
+		if err != nil {
+			return err
+		}
+		tournament.tables[i] = &table
+	}
+
+	for i := range tournament.entrants {
+		entrant := &tournament.entrants[i]
+		entrant.Table = i % numTables
+		entrant.Seat = i / numTables
+		table := tournament.tables[entrant.Table]
+		if _, err := table.AddPlayer(entrant.Name, entrant.Seat); err != nil {
+			return err
+		}
+		table.adjustBuyIns(entrant.Seat, func(buyIns *BuyIns) { *buyIns = entrant.BuyIns })
+	}
+
+	return nil
+}
+
+// Started tells if the entrants have been seated
+func (tournament *MultiTableTournament) Started() bool {
+	return len(tournament.tables) > 0
+}
+
+// Prizes gets the prize for each place paid, starting with first
+func (tournament *MultiTableTournament) Prizes() ([]int, error) {
+	buyIns := make([]BuyIns, 0, len(tournament.entrants))
+	for _, entrant := range tournament.entrants {
+		buyIns = append(buyIns, entrant.BuyIns)
+	}
+	return tournament.options.prizes(buyIns)
+}
+
+// Over tells if one entrant has won all of the chips
+func (tournament *MultiTableTournament) Over() bool {
+	return tournament.Started() && tournament.remaining() < 2
+}
+
+// Entrants gets everyone registered in the tournament
+func (tournament *MultiTableTournament) Entrants() []Entrant {
+	return tournament.entrants
+}
+
+// Entrant gets the entrant with id. Returns false if there is no such entrant
+func (tournament *MultiTableTournament) Entrant(id int) (Entrant, bool) {
+	if id < 0 || id >= len(tournament.entrants) {
+		return Entrant{}, false
+	}
+	return tournament.entrants[id], true
+}
+
+// Table gets the game being played at a table. Returns false if the table doesn't exist or
+// has been broken up
+func (tournament *MultiTableTournament) Table(table int) (*Game, bool) {
+	game, ok := tournament.tables[table]
+	return game, ok
+}
+
+// Tables gets the numbers of the tables still in play, in order
+func (tournament *MultiTableTournament) Tables() []int {
+	result := make([]int, 0, len(tournament.tables))
+	for table := range tournament.tables {
+		result = append(result, table)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// Moves gets every move of a player between tables, oldest first
+func (tournament *MultiTableTournament) Moves() []TableMove {
+	return tournament.moves
+}
+
+// Levels gets the blind schedule
+func (tournament *MultiTableTournament) Levels() []BlindLevel {
+	return tournament.schedule.levels
+}
+
+// CurrentLevel gets the index of the blind level being played
+func (tournament *MultiTableTournament) CurrentLevel() int {
+	tournament.schedule.update()
+	return tournament.schedule.level
+}
+
+// OnBreak tells if the tournament is on break, and when the break ends
+func (tournament *MultiTableTournament) OnBreak() (bool, time.Time) {
+	tournament.schedule.update()
+	return tournament.schedule.onBreak, tournament.schedule.breakEnds
+}
+
+// Standings gets the entrants who have finished, best place first
+func (tournament *MultiTableTournament) Standings() []Finish {
+	result := append([]Finish{}, tournament.finishes...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Place < result[j].Place
+	})
+	return result
+}
+
+// StartHand balances the tables and starts the next hand at table
+func (tournament *MultiTableTournament) StartHand(table int) error {
+	if !tournament.Started() {
+		return fmt.Errorf("Tournament has not started yet")
+	}
+	if tournament.Over() {
+		return fmt.Errorf("Tournament is over")
+	}
+
+	game, ok := tournament.tables[table]
+	if !ok {
+		return fmt.Errorf("Table %d is not in play", table)
+	}
+
+	if game.HandInProgress() {
+		return fmt.Errorf("Hand %d is still in progress at table %d", game.handsPlayed, table)
+	}
+
+	tournament.balance()
+	if _, ok := tournament.tables[table]; !ok {
+		return fmt.Errorf("Table %d was broken up", table)
+	}
+	if tournament.breakingTable() == table {
+		return fmt.Errorf("Table %d is being broken up once the other tables finish their hands", table)
+	}
+	if len(game.activeSeats()) < 2 {
+		return fmt.Errorf("Table %d is waiting for players to be moved", table)
+	}
+
+	if err := tournament.schedule.startHand(game); err != nil {
+		return err
+	}
+	for seat, player := range game.players {
+		tournament.startingChips[tournament.entrantAt(table, seat)] = player.Chips
+	}
+	if err := game.StartHand(); err != nil {
+		return err
+	}
+
+	tournament.finishHand(table)
+	return nil
+}
+
+// Act takes an action for the entrant with id at whichever table they are sitting at
+func (tournament *MultiTableTournament) Act(id int, action Action) error {
+	entrant, ok := tournament.Entrant(id)
+	if !ok || entrant.Table == -1 {
+		return fmt.Errorf("Entrant %d is not sitting at a table", id)
+	}
+
+	if err := tournament.tables[entrant.Table].Act(entrant.Seat, action); err != nil {
+		return err
+	}
+
+	tournament.finishHand(entrant.Table)
+	return nil
+}
+
+// ChooseRuns is a vote by the entrant with id for how many times to run the board
+func (tournament *MultiTableTournament) ChooseRuns(id int, runs int) error {
+	entrant, ok := tournament.Entrant(id)
+	if !ok || entrant.Table == -1 {
+		return fmt.Errorf("Entrant %d is not sitting at a table", id)
+	}
+
+	if err := tournament.tables[entrant.Table].ChooseRuns(entrant.Seat, runs); err != nil {
+		return err
+	}
+
+	tournament.finishHand(entrant.Table)
+	return nil
+}
+
+// finishHand knocks out every player at table who ran out of chips in the hand which just
+// ended, and rebalances the tables
+func (tournament *MultiTableTournament) finishHand(table int) {
+	game := tournament.tables[table]
+	if game.HandInProgress() || tournament.lastHand[table] == game.handsPlayed {
+		return
+	}
+	tournament.lastHand[table] = game.handsPlayed
+
+	busted := make([]int, 0)
+	starting := make([]int, 0)
+	for _, seat := range game.hand.seats {
+		if game.players[seat].Chips == 0 {
+			id := tournament.entrantAt(table, seat)
+			busted = append(busted, id)
+			starting = append(starting, tournament.startingChips[id])
+		}
+	}
+
+	remaining := tournament.remaining() - len(busted)
+	for i, place := range knockoutPlaces(starting, remaining) {
+		entrant := &tournament.entrants[busted[i]]
+		tournament.finishes = append(tournament.finishes, Finish{
+			Entrant: entrant.ID,
+			Seat:    entrant.Seat,
+			Name:    entrant.Name,
+			Place:   place,
+			Hand:    game.handsPlayed,
+		})
+		delete(game.players, entrant.Seat)
+		entrant.Table = -1
+		entrant.Seat = -1
+	}
+
+	if remaining == 1 {
+		for _, entrant := range tournament.entrants {
+			if entrant.Table != -1 {
+				tournament.finishes = append(tournament.finishes, Finish{
+					Entrant: entrant.ID,
+					Seat:    entrant.Seat,
+					Name:    entrant.Name,
+					Place:   1,
+					Hand:    game.handsPlayed,
+				})
+			}
+		}
+		return
+	}
+
+	tournament.balance()
+}
+
+// tableSize gets the number of seats at each table
+func (tournament *MultiTableTournament) tableSize() int {
+	return tournament.options.Game.tableSize()
+}
+
+// breakingTable gets the table to break up when everyone left fits at one less table, or -1
+// when every table is still needed
+func (tournament *MultiTableTournament) breakingTable() int {
+	if len(tournament.tables) < 2 || tournament.remaining() > (len(tournament.tables)-1)*tournament.tableSize() {
+		return -1
+	}
+	return tournament.smallestTable(-1)
+}
+
+// balance breaks up a table when everyone left fits at one less table, then moves players
+// from the biggest table to the smallest until they are within one player of each other.
+// Players are only moved between tables which are both between hands
+func (tournament *MultiTableTournament) balance() {
+	tournament.seatReEntries()
+	for smallest := tournament.breakingTable(); smallest != -1; smallest = tournament.breakingTable() {
+		game := tournament.tables[smallest]
+		if game.HandInProgress() {
+			break
+		}
+
+		// Players are only moved to tables between hands, so breaking a table can take a few
+		// hands elsewhere to finish
+		for _, seat := range sortedSeats(game.players) {
+			toTable := tournament.smallestTable(smallest)
+			if tournament.tables[toTable].HandInProgress() {
+				return
+			}
+			player := game.players[seat]
+			delete(game.players, seat)
+			tournament.move(smallest, seat, player, toTable, true)
+		}
+		delete(tournament.tables, smallest)
+	}
+
+	for len(tournament.tables) > 1 {
+		biggest := tournament.biggestTable()
+		smallest := tournament.smallestTable(biggest)
+		game := tournament.tables[biggest]
+		if len(game.players)-len(tournament.tables[smallest].players) < 2 || game.HandInProgress() ||
+			tournament.tables[smallest].HandInProgress() {
+			return
+		}
+
+		// The player due to post the big blind moves, so no one skips or pays it twice
+		seat := game.upcomingBigBlind()
+		player := game.players[seat]
+		delete(game.players, seat)
+		tournament.move(biggest, seat, player, smallest, false)
+	}
+}
+
+// move seats player, who was at seat at table, in the worst position at toTable. The table
+// being moved to can't be in the middle of a hand, or the player would be dealt in out of turn
+func (tournament *MultiTableTournament) move(table int, seat int, player Player, toTable int, broken bool) {
+	id := tournament.entrantAt(table, seat)
+	game := tournament.tables[toTable]
+	toSeat := game.worstEmptySeat()
+
+	player.Seat = toSeat
+	game.players[toSeat] = player
+
+	entrant := &tournament.entrants[id]
+	entrant.Table = toTable
+	entrant.Seat = toSeat
+	tournament.moves = append(tournament.moves, TableMove{
+		Entrant:   id,
+		FromTable: table,
+		FromSeat:  seat,
+		ToTable:   toTable,
+		ToSeat:    toSeat,
+		Broken:    broken,
+	})
+}
+
+// smallestTable gets the table with the fewest players, ignoring skip. Ties go to the
+// highest numbered table, so the lower tables are the ones which make the final table
+func (tournament *MultiTableTournament) smallestTable(skip int) int {
+	result := -1
+	for _, table := range tournament.Tables() {
+		if table == skip {
+			continue
+		}
+		if result == -1 || len(tournament.tables[table].players) <= len(tournament.tables[result].players) {
+			result = table
+		}
+	}
+	return result
+}
+
+// biggestTable gets the table with the most players, with ties going to the lowest number
+func (tournament *MultiTableTournament) biggestTable() int {
+	result := -1
+	for _, table := range tournament.Tables() {
+		if result == -1 || len(tournament.tables[table].players) > len(tournament.tables[result].players) {
+			result = table
+		}
+	}
+	return result
+}
+
+// remaining is the number of entrants who have not been knocked out, including those waiting
+// to re-enter
+func (tournament *MultiTableTournament) remaining() int {
+	result := len(tournament.reEntries)
+	for _, entrant := range tournament.entrants {
+		if entrant.Table != -1 {
+			result++
+		}
+	}
+	return result
+}
+
+func (tournament *MultiTableTournament) entrantAt(table int, seat int) int {
+	for _, entrant := range tournament.entrants {
+		if entrant.Table == table && entrant.Seat == seat {
+			return entrant.ID
+		}
+	}
+	return -1
+}
+
+func sortedSeats(players map[int]Player) []int {
+	result := make([]int, 0, len(players))
+	for seat := range players {
+		result = append(result, seat)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// upcomingBigBlind gets the seat of the player who will post the big blind next hand
+func (game *Game) upcomingBigBlind() int {
+	order := game.nextHandOrder(-1)
+	return order[bigBlindIndex(len(order))]
+}
+
+// worstEmptySeat gets the empty seat where a new player would have to post the big blind
+// the soonest
+func (game *Game) worstEmptySeat() int {
+	best, bestHands := -1, game.tableSize+1
+	for _, seat := range game.EmptySeats() {
+		order := game.nextHandOrder(seat)
+		bigBlind := bigBlindIndex(len(order))
+		hands := (indexOf(order, seat) - bigBlind + len(order)) % len(order)
+		if hands < bestHands {
+			best, bestHands = seat, hands
+		}
+	}
+	return best
+}
+
+// nextHandOrder gets the seats of the players with chips starting with who will have the
+// button next hand, including a new player at extra if it is not -1
+func (game *Game) nextHandOrder(extra int) []int {
+	result := make([]int, 0, game.tableSize)
+	for i := 1; i <= game.tableSize; i++ {
+		seat := (game.button + i + game.tableSize) % game.tableSize
+		if player, ok := game.players[seat]; (ok && player.dealtIn()) || seat == extra {
+			result = append(result, seat)
+		}
+	}
+	return result
+}
+
+// bigBlindIndex is the position of the big blind from the button with players in the hand
+func bigBlindIndex(players int) int {
+	if players <= 2 {
+		return 1
+	}
+	return 2
+}
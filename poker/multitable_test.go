@@ -0,0 +1,185 @@
+package poker
+
+import (
+	"testing"
+)
+
+func newTestMultiTable(t *testing.T, entrants int) *MultiTableTournament {
+	tournament, err := NewMultiTableTournament(TournamentOptions{
+		Game:   GameOptions{StarterChips: 100},
+		Levels: []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < entrants; i++ {
+		if _, err := tournament.Register("player"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tournament.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return &tournament
+}
+
+// knockOut plays a hand at table where loser goes all in against winner and loses
+func knockOut(t *testing.T, tournament *MultiTableTournament, table int, loser int, winner int) {
+	t.Helper()
+	if err := tournament.StartHand(table); err != nil {
+		t.Fatal(err)
+	}
+
+	game := tournament.tables[table]
+	game.hand.down[winner] = buildCards([]string{"AS", "AH"})
+	game.hand.down[loser] = buildCards([]string{"KS", "KH"})
+	stackDeck(game, buildCards([]string{"2C", "7D", "9C", "3H", "4D"}))
+
+	for game.HandInProgress() {
+		seat := game.hand.toAct
+		action := Action{Type: Fold}
+		if options, _ := game.ActionOptions(seat); seat == loser {
+			action = Action{Type: Raise, Amount: game.players[seat].Chips + game.hand.bets[seat]}
+		} else if seat == winner && options.ToCall > 0 {
+			action = Action{Type: Call}
+		} else if seat == winner {
+			action = Action{Type: Check}
+		}
+
+		if err := tournament.Act(tournament.entrantAt(table, seat), action); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMultiTableSeating(t *testing.T) {
+	tournament := newTestMultiTable(t, 17)
+	if len(tournament.Tables()) != 3 {
+		t.Fatal(tournament.Tables())
+	}
+	for table, size := range []int{6, 6, 5} {
+		if game, _ := tournament.Table(table); len(game.Players()) != size {
+			t.Error("Tables should be as even as possible")
+		}
+	}
+
+	if _, err := tournament.Register("late"); err == nil {
+		t.Error("Registration closes when the tournament starts")
+	}
+	if entrant, _ := tournament.Entrant(4); entrant.Table != 1 || entrant.Seat != 1 {
+		t.Error(entrant)
+	}
+}
+
+func TestMultiTableBalancing(t *testing.T) {
+	tournament := newTestMultiTable(t, 12)
+	if err := tournament.StartHand(0); err != nil {
+		t.Fatal(err)
+	}
+
+	game := tournament.tables[0]
+	game.hand.down[0] = buildCards([]string{"AS", "AH"})
+	game.hand.down[3] = buildCards([]string{"KS", "KH"})
+	game.hand.down[4] = buildCards([]string{"QS", "QH"})
+	stackDeck(game, buildCards([]string{"2C", "7D", "9C", "3H", "4D"}))
+
+	tournament.Act(tournament.entrantAt(0, 3), Action{Type: Raise, Amount: 100})
+	tournament.Act(tournament.entrantAt(0, 4), Action{Type: Call})
+	tournament.Act(tournament.entrantAt(0, 5), Action{Type: Fold})
+	tournament.Act(tournament.entrantAt(0, 0), Action{Type: Call})
+	tournament.Act(tournament.entrantAt(0, 1), Action{Type: Fold})
+	if err := tournament.Act(tournament.entrantAt(0, 2), Action{Type: Fold}); err != nil {
+		t.Fatal(err)
+	}
+
+	standings := tournament.Standings()
+	if len(standings) != 2 || standings[0].Place != 11 || standings[1].Place != 11 {
+		t.Error("Players knocked out with the same stack tie", standings)
+	}
+
+	moves := tournament.Moves()
+	if len(moves) != 1 || moves[0].FromTable != 1 || moves[0].FromSeat != 2 || moves[0].ToTable != 0 || moves[0].ToSeat != 3 {
+		t.Fatal("The next big blind moves to the worst seat", moves)
+	}
+	if entrant, _ := tournament.Entrant(moves[0].Entrant); entrant.Table != 0 || entrant.Seat != 3 {
+		t.Error(entrant)
+	}
+	if len(tournament.tables[0].Players()) != 5 || len(tournament.tables[1].Players()) != 5 {
+		t.Error()
+	}
+}
+
+func TestMultiTableFinalTable(t *testing.T) {
+	tournament := newTestMultiTable(t, 9)
+	knockOut(t, tournament, 0, 3, 0)
+
+	if tables := tournament.Tables(); len(tables) != 1 || tables[0] != 0 {
+		t.Fatal("Everyone fits at the final table", tables)
+	}
+	if len(tournament.tables[0].Players()) != 8 {
+		t.Error()
+	}
+	for _, move := range tournament.Moves() {
+		if !move.Broken || move.FromTable != 1 {
+			t.Error(move)
+		}
+	}
+	if err := tournament.StartHand(1); err == nil {
+		t.Error("Table 1 was broken")
+	}
+	if err := tournament.StartHand(0); err != nil {
+		t.Error(err)
+	}
+}
+
+// foldTable has everyone fold until the hand at table is over
+func foldTable(t *testing.T, tournament *MultiTableTournament, table int) {
+	t.Helper()
+	game := tournament.tables[table]
+	for game.HandInProgress() {
+		if err := tournament.Act(tournament.entrantAt(table, game.hand.toAct), Action{Type: Fold}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMultiTableMovesWaitForHands(t *testing.T) {
+	tournament := newTestMultiTable(t, 13)
+	if err := tournament.StartHand(0); err != nil {
+		t.Fatal(err)
+	}
+	knockOut(t, tournament, 1, 3, 0)
+	if err := tournament.StartHand(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Table 0 is free to move a player once its hand is over, but table 1 is still playing
+	foldTable(t, tournament, 0)
+	if moves := tournament.Moves(); len(moves) != 0 {
+		t.Fatal("Players can't be moved into the middle of a hand", moves)
+	}
+	foldTable(t, tournament, 1)
+	if moves := tournament.Moves(); len(moves) != 1 || moves[0].FromTable != 0 || moves[0].ToTable != 1 {
+		t.Error(moves)
+	}
+}
+
+func TestMultiTableBreakWaitsForHands(t *testing.T) {
+	tournament := newTestMultiTable(t, 9)
+	if err := tournament.StartHand(0); err != nil {
+		t.Fatal(err)
+	}
+	knockOut(t, tournament, 1, 3, 0)
+
+	if moves := tournament.Moves(); len(moves) != 0 {
+		t.Fatal("Players can't be moved into the middle of a hand", moves)
+	}
+	if err := tournament.StartHand(1); err == nil {
+		t.Error("Table 1 is waiting to be broken up")
+	}
+	foldTable(t, tournament, 0)
+	if tables := tournament.Tables(); len(tables) != 1 || tables[0] != 0 || len(tournament.Moves()) != 3 {
+		t.Error(tables, tournament.Moves())
+	}
+}
@@ -0,0 +1,132 @@
+package poker
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxICMPlayers is the most stacks the ICM calculator will take, since the work grows
+// exponentially with the number of players. A final table is as far as ICM is used in practice
+const MaxICMPlayers int = 12
+
+// PayoutStructure is how a tournament's prize pool is split up by finishing place
+type PayoutStructure struct {
+	// Percentages are the share of the prize pool for each place, starting with first. They
+	// must add up to 100
+	Percentages []float64 `json:"percentages"`
+}
+
+// standardPayouts are the templates for payouts, by the most entrants they are used for
+var standardPayouts = []struct {
+	maxEntrants int
+	percentages []float64
+}{
+	{4, []float64{100}},
+	{7, []float64{65, 35}},
+	{15, []float64{50, 30, 20}},
+	{25, []float64{40, 25, 20, 15}},
+	{40, []float64{35, 22, 16, 12, 9, 6}},
+	{math.MaxInt32, []float64{30, 20, 14, 10, 8, 6.5, 5, 3.5, 3}},
+}
+
+// StandardPayouts gets the usual payouts for a tournament with the number of entrants. Bigger
+// fields pay more places
+func StandardPayouts(entrants int) PayoutStructure {
+	for _, template := range standardPayouts {
+		if entrants <= template.maxEntrants {
+			return PayoutStructure{Percentages: append([]float64{}, template.percentages...)}
+		}
+	}
+	return PayoutStructure{}
+}
+
+func (payouts *PayoutStructure) validate() error {
+	if len(payouts.Percentages) == 0 {
+		return fmt.Errorf("Payouts need at least one place paid")
+	}
+
+	total := 0.0
+	for i, percentage := range payouts.Percentages {
+		if percentage <= 0 {
+			return fmt.Errorf("Place %d needs a positive payout", i+1)
+		}
+		if i > 0 && percentage > payouts.Percentages[i-1] {
+			return fmt.Errorf("Place %d cannot pay more than place %d", i+1, i)
+		}
+		total += percentage
+	}
+
+	if math.Abs(total-100) > 0.0001 {
+		return fmt.Errorf("Payout percentages add up to %g, not 100", total)
+	}
+	return nil
+}
+
+// Prizes splits prizePool by place. Amounts are rounded down, with what is left over from
+// rounding going to first place
+func (payouts PayoutStructure) Prizes(prizePool int) ([]int, error) {
+	if err := payouts.validate(); err != nil {
+		return nil, err
+	}
+	if prizePool < 0 {
+		return nil, fmt.Errorf("Prize pool cannot be negative")
+	}
+
+	result := make([]int, len(payouts.Percentages))
+	paid := 0
+	for i, percentage := range payouts.Percentages {
+		result[i] = int(float64(prizePool) * percentage / 100)
+		paid += result[i]
+	}
+	result[0] += prizePool - paid
+	return result, nil
+}
+
+// ICM converts chip stacks into shares of the prizes with the Independent Chip Model. A player's
+// chance of finishing in each place is worked out from the order players would finish in, where
+// each place goes to someone left with a chance proportional to their stack
+func ICM(stacks []int, prizes []int) ([]float64, error) {
+	if len(stacks) == 0 || len(stacks) > MaxICMPlayers {
+		return nil, fmt.Errorf("ICM needs between 1 and %d stacks", MaxICMPlayers)
+	}
+
+	total := 0
+	for i, stack := range stacks {
+		if stack <= 0 {
+			return nil, fmt.Errorf("Stack %d needs to have chips", i+1)
+		}
+		total += stack
+	}
+	for i, prize := range prizes {
+		if prize < 0 {
+			return nil, fmt.Errorf("Prize for place %d cannot be negative", i+1)
+		}
+	}
+
+	// layer maps each set of players who took the top places, as a bit mask, to its chance
+	equity := make([]float64, len(stacks))
+	layer := map[uint32]float64{0: 1}
+	for place := 0; place < len(prizes) && place < len(stacks); place++ {
+		next := make(map[uint32]float64)
+		for taken, chance := range layer {
+			left := total
+			for i, stack := range stacks {
+				if taken&(1<<uint(i)) != 0 {
+					left -= stack
+				}
+			}
+
+			for i, stack := range stacks {
+				if taken&(1<<uint(i)) != 0 {
+					continue
+				}
+				finishes := chance * float64(stack) / float64(left)
+				equity[i] += finishes * float64(prizes[place])
+				next[taken|1<<uint(i)] += finishes
+			}
+		}
+		layer = next
+	}
+
+	return equity, nil
+}
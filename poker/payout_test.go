@@ -0,0 +1,72 @@
+package poker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStandardPayouts(t *testing.T) {
+	for _, entrants := range []int{2, 6, 9, 20, 30, 100} {
+		payouts := StandardPayouts(entrants)
+		if err := payouts.validate(); err != nil {
+			t.Error(entrants, err)
+		}
+	}
+
+	if len(StandardPayouts(9).Percentages) != 3 {
+		t.Error("Nine entrants pay three places")
+	}
+}
+
+func TestPrizes(t *testing.T) {
+	prizes, err := PayoutStructure{Percentages: []float64{50, 30, 20}}.Prizes(1001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prizes[0] != 501 || prizes[1] != 300 || prizes[2] != 200 {
+		t.Error("Rounding goes to first place", prizes)
+	}
+
+	if _, err := (PayoutStructure{Percentages: []float64{50, 30}}).Prizes(1000); err == nil {
+		t.Error("Percentages need to add up to 100")
+	}
+	if _, err := (PayoutStructure{Percentages: []float64{30, 70}}).Prizes(1000); err == nil {
+		t.Error("Lower places can't pay more")
+	}
+}
+
+func closeTo(a float64, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+func TestICM(t *testing.T) {
+	equity, err := ICM([]int{5000, 3000, 2000}, []int{50, 30, 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Worked out by hand from the finishing order chances
+	if !closeTo(equity[0], 38.39) || !closeTo(equity[1], 32.75) || !closeTo(equity[2], 28.86) {
+		t.Error(equity)
+	}
+	if !closeTo(equity[0]+equity[1]+equity[2], 100) {
+		t.Error("All of the prizes are handed out")
+	}
+
+	equity, _ = ICM([]int{1000, 1000}, []int{70, 30})
+	if !closeTo(equity[0], 50) || !closeTo(equity[1], 50) {
+		t.Error("Equal stacks have equal equity")
+	}
+
+	if _, err := ICM([]int{1000, 0}, []int{100}); err == nil {
+		t.Error()
+	}
+
+	stacks := make([]int, MaxICMPlayers+1)
+	for i := range stacks {
+		stacks[i] = 1000
+	}
+	if _, err := ICM(stacks, []int{100}); err == nil {
+		t.Error("Too many stacks to work out")
+	}
+}
@@ -0,0 +1,50 @@
+package poker
+
+import (
+	"fmt"
+)
+
+// Rake is what the house takes out of each pot in a cash game
+type Rake struct {
+	// Percent is the share of each pot taken, 0 for no rake
+	Percent float64 `json:"percent"`
+	// Cap is the most taken from a single hand, 0 for no cap
+	Cap int `json:"cap"`
+	// NoFlopNoDrop skips the rake for hands which end in the first betting round
+	NoFlopNoDrop bool `json:"noFlopNoDrop"`
+}
+
+func (rake *Rake) validate() error {
+	if rake.Percent < 0 || rake.Percent > 100 || rake.Cap < 0 {
+		return fmt.Errorf("Rake needs to be between 0 and 100 percent, with a cap that isn't negative")
+	}
+	if rake.Percent == 0 && rake.Cap > 0 {
+		return fmt.Errorf("Rake cap needs a rake percent")
+	}
+	return nil
+}
+
+// Rake gets what the house takes out of each pot
+func (game *Game) Rake() Rake {
+	return game.rake
+}
+
+// RakeCollected gets everything the house has taken out of pots. It is not part of any
+// player's chips, so the ledger's nets add up to minus the rake
+func (game *Game) RakeCollected() int {
+	return game.rakeCollected
+}
+
+// takeRake gets the rake for a pot of amount, when taken has already been raked from the
+// rest of the hand. Rounds down in the players' favor
+func (game *Game) takeRake(amount int, taken int) int {
+	if game.rake.Percent == 0 || (game.rake.NoFlopNoDrop && game.hand.street == 0) {
+		return 0
+	}
+
+	rake := int(float64(amount) * game.rake.Percent / 100)
+	if game.rake.Cap > 0 && taken+rake > game.rake.Cap {
+		rake = game.rake.Cap - taken
+	}
+	return rake
+}
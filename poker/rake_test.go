@@ -0,0 +1,79 @@
+package poker
+
+import (
+	"testing"
+)
+
+func checkDownHand(t *testing.T, game *Game) {
+	t.Helper()
+	if err := game.StartHand(); err != nil {
+		t.Fatal(err)
+	}
+	for game.HandInProgress() {
+		action := Action{Type: Check}
+		if options, _ := game.ActionOptions(game.hand.toAct); options.ToCall > 0 {
+			action.Type = Call
+		}
+		mustAct(t, game, game.hand.toAct, action)
+	}
+}
+
+func TestRake(t *testing.T) {
+	game := newTestGame(t, GameOptions{Rake: Rake{Percent: 5, NoFlopNoDrop: true}}, 0, 1, 2)
+	foldHand(t, &game)
+	if game.RakeCollected() != 0 {
+		t.Error("No flop, no drop")
+	}
+
+	checkDownHand(t, &game)
+	record := game.History()[1]
+	if record.Rake != 1 || record.Pots[0].Amount != 30 || record.Pots[0].Rake != 1 || game.RakeCollected() != 1 {
+		t.Error(record.Pots)
+	}
+
+	net := 0
+	for _, entry := range game.Ledger() {
+		net += entry.Net
+	}
+	if net != -1 {
+		t.Error("Rake is taken out of the players' chips", net)
+	}
+
+	// Settling up pays the rake to the house, so what's paid out is everything that was lost
+	lost, paid, house := 0, 0, 0
+	for _, entry := range game.Ledger() {
+		if entry.Net < 0 {
+			lost -= entry.Net
+		}
+	}
+	transfers := game.Settle()
+	for _, transfer := range transfers {
+		paid += transfer.Amount
+		if transfer.ToSeat == HouseSeat {
+			house += transfer.Amount
+		}
+	}
+	if house != 1 || paid != lost {
+		t.Error("House is paid the rake", transfers)
+	}
+}
+
+func TestRakeCap(t *testing.T) {
+	game := newTestGame(t, GameOptions{Rake: Rake{Percent: 10, Cap: 2}}, 0, 1, 2)
+	foldHand(t, &game)
+	if game.RakeCollected() != 1 {
+		t.Error("Pot of 15 is raked without no flop no drop")
+	}
+
+	checkDownHand(t, &game)
+	if game.History()[1].Rake != 2 || game.RakeCollected() != 3 {
+		t.Error(game.History()[1].Rake)
+	}
+
+	if _, err := NewGameWithOptions(GameOptions{StarterChips: 100, BlindSize: 10, Rake: Rake{Percent: 101}}); err == nil {
+		t.Error("Cannot rake more than the pot")
+	}
+	if _, err := NewTournament(TournamentOptions{Game: GameOptions{StarterChips: 100, Rake: Rake{Percent: 5}}, Levels: []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}}}}); err == nil {
+		t.Error("Tournaments are not raked")
+	}
+}
@@ -0,0 +1,362 @@
+package poker
+
+import (
+	"fmt"
+)
+
+// Unlimited is the limit on rebuys or re-entries when there is no limit
+const Unlimited int = -1
+
+// BuyIns are everything a player has paid to play
+type BuyIns struct {
+	// Initial is what the player paid to sit down, and Total is everything they have paid. In
+	// cash games it is the same as the chips bought
+	Initial   int  `json:"initial"`
+	Total     int  `json:"total"`
+	Rebuys    int  `json:"rebuys"`
+	ReEntries int  `json:"reEntries"`
+	AddOn     bool `json:"addOn"`
+}
+
+// RebuyRules are when players can buy more chips
+type RebuyRules struct {
+	// MaxRebuys is how many times each player can rebuy, 0 for no rebuys or Unlimited
+	MaxRebuys int `json:"maxRebuys"`
+	// Threshold is the most chips a player can have and still rebuy, defaulting to the starting
+	// chips
+	Threshold int `json:"threshold"`
+	// Chips are the chips for each rebuy, defaulting to the starting chips
+	Chips int `json:"chips"`
+}
+
+func (rebuys *RebuyRules) validate() error {
+	if rebuys.MaxRebuys < Unlimited || rebuys.Threshold < 0 || rebuys.Chips < 0 {
+		return fmt.Errorf("Rebuys cannot have a negative limit, threshold or chips")
+	}
+	return nil
+}
+
+// AddOn is a one time purchase of chips offered to every player during a level of a tournament
+type AddOn struct {
+	// Level is the index of the level the add-on is offered at
+	Level int `json:"level"`
+	// Chips are the chips for the add-on, with 0 meaning there is no add-on
+	Chips int `json:"chips"`
+	Cost  int `json:"cost"`
+}
+
+func underLimit(count int, limit int) bool {
+	return limit == Unlimited || count < limit
+}
+
+// Rebuys gets the rules for players buying more chips
+func (game *Game) Rebuys() RebuyRules {
+	return game.rebuys
+}
+
+// Rebuy buys the player in seat more chips in a cash game, paying for them with the chips' value
+func (game *Game) Rebuy(seat int) error {
+	chips, err := game.checkRebuy(seat, game.players[seat].BuyIns.Rebuys)
+	if err != nil {
+		return err
+	}
+
+	game.buyChips(seat, chips, chips)
+	game.adjustBuyIns(seat, func(buyIns *BuyIns) { buyIns.Rebuys++ })
+	return nil
+}
+
+// checkRebuy makes sure the player in seat can rebuy, when they have rebought rebuys times
+// already. Returns the chips they would get
+func (game *Game) checkRebuy(seat int, rebuys int) (int, error) {
+	player, ok := game.players[seat]
+	if !ok {
+		return 0, fmt.Errorf("No player at seat %d", seat)
+	}
+	if err := game.checkBetweenHands(seat); err != nil {
+		return 0, err
+	}
+
+	if game.rebuys.MaxRebuys == 0 {
+		return 0, fmt.Errorf("Game does not allow rebuys")
+	}
+	if !underLimit(rebuys, game.rebuys.MaxRebuys) {
+		return 0, fmt.Errorf("Seat %d has already rebought the most times allowed, %d", seat, game.rebuys.MaxRebuys)
+	}
+	if threshold := game.rebuyThreshold(); player.Chips > threshold {
+		return 0, fmt.Errorf("Can only rebuy with %d chips or less", threshold)
+	}
+
+	if game.rebuys.Chips > 0 {
+		return game.rebuys.Chips, nil
+	}
+	return game.starterChips, nil
+}
+
+// rebuyThreshold is the most chips a player can have and still rebuy
+func (game *Game) rebuyThreshold() int {
+	if game.rebuys.Threshold == 0 {
+		return game.starterChips
+	}
+	return game.rebuys.Threshold
+}
+
+// checkBetweenHands makes sure the player in seat is not playing a hand
+func (game *Game) checkBetweenHands(seat int) error {
+	if game.HandInProgress() && indexOf(game.hand.seats, seat) != -1 {
+		return fmt.Errorf("Seat %d can only buy chips between hands", seat)
+	}
+	return nil
+}
+
+// buyChips gives the player in seat chips, which they paid cost for
+func (game *Game) buyChips(seat int, chips int, cost int) {
+	game.adjustChips(seat, chips)
+	game.adjustBuyIns(seat, func(buyIns *BuyIns) { buyIns.Total += cost })
+}
+
+func (game *Game) adjustBuyIns(seat int, adjust func(*BuyIns)) {
+	player := game.players[seat]
+	adjust(&player.BuyIns)
+	game.players[seat] = player
+}
+
+// checkRebuyPeriod makes sure it is still early enough in the tournament to buy back in
+func (options *TournamentOptions) checkRebuyPeriod(level int) error {
+	if options.RebuyLevels > 0 && level >= options.RebuyLevels {
+		return fmt.Errorf("Rebuys and re-entries closed after level %d", options.RebuyLevels)
+	}
+	return nil
+}
+
+// checkAddOn makes sure the add-on is being offered at level, and buyIns hasn't taken it yet
+func (options *TournamentOptions) checkAddOn(level int, buyIns BuyIns) error {
+	if options.AddOn.Chips == 0 {
+		return fmt.Errorf("Tournament does not have an add-on")
+	}
+	if level != options.AddOn.Level {
+		return fmt.Errorf("Add-on is only offered during level %d", options.AddOn.Level+1)
+	}
+	if buyIns.AddOn {
+		return fmt.Errorf("Add-on has already been taken")
+	}
+	return nil
+}
+
+// checkReEntry makes sure another entry is allowed for someone with buyIns
+func (options *TournamentOptions) checkReEntry(level int, buyIns BuyIns) error {
+	if options.MaxReEntries == 0 {
+		return fmt.Errorf("Tournament does not allow re-entry")
+	}
+	if !underLimit(buyIns.ReEntries, options.MaxReEntries) {
+		return fmt.Errorf("Already re-entered the most times allowed, %d", options.MaxReEntries)
+	}
+	return options.checkRebuyPeriod(level)
+}
+
+// Rebuy buys the player in seat more chips for the tournament's buy in
+func (tournament *Tournament) Rebuy(seat int) error {
+	if _, finished := tournament.Place(seat); finished {
+		return fmt.Errorf("Seat %d has been knocked out, and needs to re-enter", seat)
+	}
+	if err := tournament.options.checkRebuyPeriod(tournament.CurrentLevel()); err != nil {
+		return err
+	}
+	chips, err := tournament.checkRebuy(seat, tournament.players[seat].BuyIns.Rebuys)
+	if err != nil {
+		return err
+	}
+
+	tournament.buyChips(seat, chips, tournament.options.BuyIn)
+	tournament.adjustBuyIns(seat, func(buyIns *BuyIns) { buyIns.Rebuys++ })
+	return nil
+}
+
+// AddOn buys the player in seat the add-on chips
+func (tournament *Tournament) AddOn(seat int) error {
+	player, ok := tournament.players[seat]
+	if !ok || player.Chips == 0 {
+		return fmt.Errorf("Seat %d is not in the tournament", seat)
+	}
+	if err := tournament.options.checkAddOn(tournament.CurrentLevel(), player.BuyIns); err != nil {
+		return err
+	}
+	if err := tournament.checkBetweenHands(seat); err != nil {
+		return err
+	}
+
+	addOn := tournament.options.AddOn
+	tournament.buyChips(seat, addOn.Chips, addOn.Cost)
+	tournament.adjustBuyIns(seat, func(buyIns *BuyIns) { buyIns.AddOn = true })
+	return nil
+}
+
+// ReEnter buys a player who was knocked out from seat back into the tournament at newSeat,
+// which can be the seat they had
+func (tournament *Tournament) ReEnter(seat int, newSeat int) error {
+	if _, finished := tournament.Place(seat); !finished || tournament.Over() {
+		return fmt.Errorf("Seat %d has not been knocked out of a running tournament", seat)
+	}
+
+	player := tournament.players[seat]
+	if err := tournament.options.checkReEntry(tournament.CurrentLevel(), player.BuyIns); err != nil {
+		return err
+	}
+	if _, taken := tournament.players[newSeat]; taken && newSeat != seat {
+		return errorOf(ErrSeatTaken, "Already have player at seat %d", newSeat)
+	}
+	if newSeat < 0 || newSeat >= tournament.tableSize {
+		return errorOf(ErrInvalidSeat, "Invalid seat number %d", newSeat)
+	}
+
+	delete(tournament.players, seat)
+	player.Seat = newSeat
+	player.Chips = tournament.starterChips
+	player.TimeBank = tournament.shotClock.TimeBank
+	player.timeouts = 0
+	player.BuyIns.Total += tournament.options.BuyIn
+	player.BuyIns.ReEntries++
+	tournament.players[newSeat] = player
+	tournament.finishes = removeFinish(tournament.finishes, func(finish Finish) bool { return finish.Seat == seat })
+	return nil
+}
+
+// AddPlayer adds a new player to the tournament, who pays the buy in
+func (tournament *Tournament) AddPlayer(name string, seat int) (Player, error) {
+	player, err := tournament.Game.AddPlayer(name, seat)
+	if err != nil {
+		return player, err
+	}
+
+	tournament.adjustBuyIns(seat, func(buyIns *BuyIns) {
+		buyIns.Initial = tournament.options.BuyIn
+		buyIns.Total = tournament.options.BuyIn
+	})
+	return tournament.players[seat], nil
+}
+
+// removeFinish takes away the finish of a player who bought back in
+func removeFinish(finishes []Finish, matches func(Finish) bool) []Finish {
+	result := make([]Finish, 0, len(finishes))
+	for _, finish := range finishes {
+		if !matches(finish) {
+			result = append(result, finish)
+		}
+	}
+	return result
+}
+
+// Rebuy buys the entrant with id more chips for the tournament's buy in
+func (tournament *MultiTableTournament) Rebuy(id int) error {
+	entrant, ok := tournament.Entrant(id)
+	if !ok || entrant.Table == -1 {
+		return fmt.Errorf("Entrant %d is not sitting at a table, and needs to re-enter", id)
+	}
+	if err := tournament.options.checkRebuyPeriod(tournament.CurrentLevel()); err != nil {
+		return err
+	}
+
+	game := tournament.tables[entrant.Table]
+	chips, err := game.checkRebuy(entrant.Seat, entrant.BuyIns.Rebuys)
+	if err != nil {
+		return err
+	}
+
+	game.adjustChips(entrant.Seat, chips)
+	tournament.adjustBuyIns(id, func(buyIns *BuyIns) {
+		buyIns.Total += tournament.options.BuyIn
+		buyIns.Rebuys++
+	})
+	return nil
+}
+
+// AddOn buys the entrant with id the add-on chips
+func (tournament *MultiTableTournament) AddOn(id int) error {
+	entrant, ok := tournament.Entrant(id)
+	if !ok || entrant.Table == -1 {
+		return fmt.Errorf("Entrant %d is not sitting at a table", id)
+	}
+	if err := tournament.options.checkAddOn(tournament.CurrentLevel(), entrant.BuyIns); err != nil {
+		return err
+	}
+
+	game := tournament.tables[entrant.Table]
+	if err := game.checkBetweenHands(entrant.Seat); err != nil {
+		return err
+	}
+
+	addOn := tournament.options.AddOn
+	game.adjustChips(entrant.Seat, addOn.Chips)
+	tournament.adjustBuyIns(id, func(buyIns *BuyIns) {
+		buyIns.Total += addOn.Cost
+		buyIns.AddOn = true
+	})
+	return nil
+}
+
+// ReEnter buys an entrant who was knocked out back into the tournament, seating them at the
+// table with the fewest players. Like players being moved, they wait for the hand at that
+// table to finish before they are seated
+func (tournament *MultiTableTournament) ReEnter(id int) error {
+	entrant, ok := tournament.Entrant(id)
+	if !ok || !tournament.Started() || entrant.Table != -1 || tournament.reEntering(id) || tournament.Over() {
+		return fmt.Errorf("Entrant %d has not been knocked out of a running tournament", id)
+	}
+	if err := tournament.options.checkReEntry(tournament.CurrentLevel(), entrant.BuyIns); err != nil {
+		return err
+	}
+	if tournament.remaining() >= len(tournament.tables)*tournament.tableSize() {
+		return fmt.Errorf("No open seats to re-enter at")
+	}
+
+	tournament.adjustBuyIns(id, func(buyIns *BuyIns) {
+		buyIns.Total += tournament.options.BuyIn
+		buyIns.ReEntries++
+	})
+	tournament.finishes = removeFinish(tournament.finishes, func(finish Finish) bool { return finish.Entrant == id })
+	tournament.reEntries = append(tournament.reEntries, id)
+	tournament.seatReEntries()
+	return nil
+}
+
+// seatReEntries seats the entrants waiting to re-enter at the smallest tables which are between
+// hands. They owe the big blind, so they can't get a hand without paying for it
+func (tournament *MultiTableTournament) seatReEntries() {
+	for len(tournament.reEntries) > 0 {
+		table := tournament.smallestTable(tournament.breakingTable())
+		game := tournament.tables[table]
+		if game.HandInProgress() {
+			return
+		}
+
+		id := tournament.reEntries[0]
+		tournament.reEntries = tournament.reEntries[1:]
+		seat := game.worstEmptySeat()
+		game.players[seat] = Player{
+			Name:      tournament.entrants[id].Name,
+			Chips:     game.starterChips,
+			Seat:      seat,
+			BuyIns:    tournament.entrants[id].BuyIns,
+			TimeBank:  game.shotClock.TimeBank,
+			OwesBlind: true,
+			secret:    newSecret(),
+		}
+		tournament.entrants[id].Table = table
+		tournament.entrants[id].Seat = seat
+	}
+}
+
+// reEntering tells if the entrant with id has re-entered and is waiting for a seat
+func (tournament *MultiTableTournament) reEntering(id int) bool {
+	return indexOf(tournament.reEntries, id) != -1
+}
+
+// adjustBuyIns changes the buy ins of the entrant with id, keeping their player at the table
+// up to date
+func (tournament *MultiTableTournament) adjustBuyIns(id int, adjust func(*BuyIns)) {
+	entrant := &tournament.entrants[id]
+	adjust(&entrant.BuyIns)
+	if game, ok := tournament.tables[entrant.Table]; ok {
+		game.adjustBuyIns(entrant.Seat, func(buyIns *BuyIns) { *buyIns = entrant.BuyIns })
+	}
+}
@@ -0,0 +1,189 @@
+package poker
+
+import (
+	"testing"
+)
+
+func TestCashGameRebuy(t *testing.T) {
+	game := newTestGame(t, GameOptions{Rebuys: RebuyRules{MaxRebuys: 1, Threshold: 50}}, 0, 1)
+	if err := game.Rebuy(0); err == nil {
+		t.Error("Too many chips to rebuy")
+	}
+
+	setChips(&game, 0, 20)
+	if err := game.Rebuy(0); err != nil {
+		t.Fatal(err)
+	}
+	if player := game.players[0]; player.Chips != 120 || player.BuyIns.Total != 200 || player.BuyIns.Rebuys != 1 {
+		t.Error(player)
+	}
+
+	setChips(&game, 0, 20)
+	if err := game.Rebuy(0); err == nil {
+		t.Error("Only one rebuy is allowed")
+	}
+
+	game.StartHand()
+	setChips(&game, 1, 0)
+	if err := game.Rebuy(1); err == nil {
+		t.Error("Can't rebuy in the middle of a hand")
+	}
+}
+
+func TestTournamentRebuysAndAddOn(t *testing.T) {
+	tournament, err := NewTournament(TournamentOptions{
+		Game:        GameOptions{StarterChips: 100, Rebuys: RebuyRules{MaxRebuys: Unlimited, Threshold: 100}},
+		Levels:      []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}, Hands: 1}, {Blinds: Blinds{SmallBlind: 10, BigBlind: 20}}},
+		BuyIn:       20,
+		RebuyLevels: 1,
+		AddOn:       AddOn{Level: 0, Chips: 200, Cost: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tournament.AddPlayer("player", 0)
+	tournament.AddPlayer("player", 1)
+
+	if err := tournament.Rebuy(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := tournament.Rebuy(0); err == nil {
+		t.Error("Too many chips after rebuying")
+	}
+	if err := tournament.AddOn(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := tournament.AddOn(1); err == nil {
+		t.Error("Add-on is one time")
+	}
+
+	if player := tournament.players[0]; player.Chips != 200 || player.BuyIns.Total != 40 {
+		t.Error(player)
+	}
+	if player := tournament.players[1]; player.Chips != 300 || player.BuyIns.Total != 30 || !player.BuyIns.AddOn {
+		t.Error(player)
+	}
+	if prizes, _ := tournament.Prizes(); len(prizes) != 1 || prizes[0] != 70 {
+		t.Error(prizes)
+	}
+
+	tournament.StartHand()
+	tournament.Act(tournament.hand.toAct, Action{Type: Fold})
+	if err := tournament.AddOn(0); err == nil || tournament.CurrentLevel() != 1 {
+		t.Error("Add-on is over after the first level")
+	}
+}
+
+func TestTournamentRebuyDefaultThreshold(t *testing.T) {
+	tournament, err := NewTournament(TournamentOptions{
+		Game:   GameOptions{StarterChips: 100, Rebuys: RebuyRules{MaxRebuys: Unlimited}},
+		Levels: []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}}},
+		BuyIn:  20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tournament.AddPlayer("player", 0)
+	tournament.AddPlayer("player", 1)
+
+	setChips(&tournament.Game, 0, 40)
+	if err := tournament.Rebuy(0); err != nil {
+		t.Fatal("Players below the starting chips can rebuy", err)
+	}
+	if err := tournament.Rebuy(0); err == nil {
+		t.Error("Too many chips after rebuying")
+	}
+	if err := tournament.Rebuy(1); err != nil || tournament.players[1].Chips != 200 {
+		t.Error("Players with the starting chips can rebuy", err)
+	}
+}
+
+func TestTournamentReEntry(t *testing.T) {
+	tournament, err := NewTournament(TournamentOptions{
+		Game:         GameOptions{StarterChips: 100},
+		Levels:       []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}}},
+		BuyIn:        20,
+		MaxReEntries: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, seat := range []int{0, 1, 2} {
+		tournament.AddPlayer("player", seat)
+	}
+
+	tournament.StartHand()
+	tournament.hand.down[0] = buildCards([]string{"AS", "AH"})
+	tournament.hand.down[1] = buildCards([]string{"KS", "KH"})
+	stackDeck(&tournament.Game, buildCards([]string{"2C", "7D", "9C", "3H", "4D"}))
+	tournament.Act(0, Action{Type: Raise, Amount: 100})
+	tournament.Act(1, Action{Type: Call})
+	tournament.Act(2, Action{Type: Fold})
+
+	if err := tournament.ReEnter(1, 0); err == nil {
+		t.Error("Seat is taken")
+	}
+	if err := tournament.ReEnter(1, 5); err != nil {
+		t.Fatal(err)
+	}
+	if len(tournament.Standings()) != 0 || tournament.players[5].Chips != 100 || tournament.players[5].BuyIns.ReEntries != 1 {
+		t.Error("Re-entering takes back the finish")
+	}
+	if _, ok := tournament.players[1]; ok {
+		t.Error()
+	}
+	if prizes, _ := tournament.Prizes(); prizes[0] != 80 {
+		t.Error(prizes)
+	}
+}
+
+func TestMultiTableReEntry(t *testing.T) {
+	tournament, err := NewMultiTableTournament(TournamentOptions{
+		Game:         GameOptions{StarterChips: 100},
+		Levels:       []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}}},
+		BuyIn:        20,
+		MaxReEntries: Unlimited,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 12; i++ {
+		tournament.Register("player")
+	}
+	tournament.Start()
+
+	loser := tournament.entrantAt(0, 3)
+	knockOut(t, &tournament, 0, 3, 0)
+	if err := tournament.ReEnter(loser); err != nil {
+		t.Fatal(err)
+	}
+
+	entrant, _ := tournament.Entrant(loser)
+	if entrant.Table != 0 || entrant.BuyIns.Total != 40 || len(tournament.Standings()) != 0 {
+		t.Error("Re-entry goes to the smallest table", entrant)
+	}
+	if player := tournament.tables[0].players[entrant.Seat]; player.Chips != 100 || player.BuyIns.ReEntries != 1 || !player.OwesBlind {
+		t.Error(player)
+	}
+
+	// Re-entering while the table is in a hand waits for the hand to finish
+	loser = tournament.entrantAt(1, 2)
+	knockOut(t, &tournament, 1, 2, 0)
+	if err := tournament.StartHand(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := tournament.ReEnter(loser); err != nil {
+		t.Fatal(err)
+	}
+	if entrant, _ := tournament.Entrant(loser); entrant.Table != -1 || tournament.ReEnter(loser) == nil {
+		t.Error("Re-entry waits for a seat", entrant)
+	}
+	game := tournament.tables[1]
+	for game.HandInProgress() {
+		tournament.Act(tournament.entrantAt(1, game.hand.toAct), Action{Type: Fold})
+	}
+	entrant, _ = tournament.Entrant(loser)
+	if player := game.players[entrant.Seat]; entrant.Table != 1 || !player.OwesBlind {
+		t.Error("Re-entry is seated once the hand is over, owing the big blind", entrant, player)
+	}
+}
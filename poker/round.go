@@ -0,0 +1,906 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// lowQualifier is the highest card allowed in the low half of a hi/lo pot
+const lowQualifier = Eight
+
+// streetDeal is the cards dealt at the start of a betting round
+type streetDeal struct {
+	board int
+	up    int
+	down  int
+}
+
+// variantRules describe how a variant is dealt and how the pot is awarded
+type variantRules struct {
+	holeCards int
+	streets   []streetDeal
+	stud      bool
+	omaha     bool
+	hiLo      bool
+	lowball   bool
+}
+
+var flopStreets = []streetDeal{{}, {board: 3}, {board: 1}, {board: 1}}
+var studStreets = []streetDeal{{up: 1}, {up: 1}, {up: 1}, {up: 1}, {down: 1}}
+
+func rulesFor(variant Variant) variantRules {
+	switch variant {
+	case Omaha:
+		return variantRules{holeCards: 4, streets: flopStreets, omaha: true}
+	case OmahaHiLo:
+		return variantRules{holeCards: 4, streets: flopStreets, omaha: true, hiLo: true}
+	case Razz:
+		return variantRules{holeCards: 2, streets: studStreets, stud: true, lowball: true}
+	case Stud:
+		return variantRules{holeCards: 2, streets: studStreets, stud: true}
+	case StudHiLo:
+		return variantRules{holeCards: 2, streets: studStreets, stud: true, hiLo: true}
+	default:
+		return variantRules{holeCards: 2, streets: flopStreets}
+	}
+}
+
+// handState is a hand from the deal until the pot is awarded
+type handState struct {
+	gameType GameType
+	rules    variantRules
+	// seats are the players dealt in, ordered starting from the left of the button
+	seats []int
+	// order is who acts in turn for the betting round. It is the same as seats, except before
+	// the flop with a button straddle
+	order []int
+	down  map[int][]Card
+	up    map[int][]Card
+	board []Card
+	// boards are every board when running it more than once, with the first being board
+	boards       [][]Card
+	choosingRuns bool
+	runVotes     map[int]int
+	bombPot      bool
+
+	street      int
+	bets        map[int]int
+	contributed map[int]int
+	folded      map[int]bool
+	needsToAct  map[int]bool
+	// raiseOpen tracks who can still raise, since an all in for less than a full raise does not
+	// re-open the betting for players who have already acted
+	raiseOpen map[int]bool
+	toAct     int
+	// turnStarted is when the player to act was first waited on, which starts their shot clock.
+	// While choosing how many times to run it, it is when the vote started
+	turnStarted time.Time
+	currentBet  int
+	lastRaise   int
+	raises      int
+	over        bool
+	// ended is when the pots were awarded
+	ended time.Time
+
+	record HandRecord
+}
+
+// SeatView is what everyone at the table can see about a player in the hand
+type SeatView struct {
+	Bet         int    `json:"bet"`
+	Folded      bool   `json:"folded"`
+	AllIn       bool   `json:"allIn"`
+	UpCards     []Card `json:"upCards"`
+	HiddenCards int    `json:"hiddenCards"`
+	// HoleCards are only filled in for the player viewing the hand, or at showdown
+	HoleCards []Card `json:"holeCards,omitempty"`
+}
+
+// HandView is a player's view of the current hand
+type HandView struct {
+	Number  int      `json:"number"`
+	Game    GameType `json:"game"`
+	Button  int      `json:"button"`
+	Street  int      `json:"street"`
+	Board   []Card   `json:"board"`
+	Boards  [][]Card `json:"boards,omitempty"`
+	BombPot bool     `json:"bombPot"`
+	// ChoosingRuns is set when the players all in are voting on how many times to run it
+	ChoosingRuns bool        `json:"choosingRuns"`
+	RunVotes     map[int]int `json:"runVotes,omitempty"`
+	Pot          int         `json:"pot"`
+	ToAct        int         `json:"toAct"`
+	// Deadline is when the player to act runs out of time, when there is a shot clock
+	Deadline *time.Time       `json:"deadline,omitempty"`
+	Over     bool             `json:"over"`
+	Seats    map[int]SeatView `json:"seats"`
+	Options  *ActionOptions   `json:"options,omitempty"`
+	Results  []PotResult      `json:"results,omitempty"`
+}
+
+// HandInProgress tells if a hand has been dealt and is still being played
+func (game *Game) HandInProgress() bool {
+	return game.hand != nil && !game.hand.over
+}
+
+// HandView gets the current or last hand as seen by the player in viewer. Pass -1 to
+// only see what is public. Returns false if no hand has been dealt yet
+func (game *Game) HandView(viewer int) (HandView, bool) {
+	hand := game.hand
+	if hand == nil {
+		return HandView{}, false
+	}
+
+	view := HandView{
+		Number:       hand.record.Number,
+		Game:         hand.gameType,
+		Button:       hand.record.Button,
+		Street:       hand.street,
+		Board:        hand.board,
+		Boards:       hand.boards,
+		BombPot:      hand.bombPot,
+		ChoosingRuns: hand.choosingRuns,
+		RunVotes:     hand.runVotes,
+		Pot:          hand.pot(),
+		ToAct:        hand.toAct,
+		Over:         hand.over,
+		Seats:        make(map[int]SeatView),
+	}
+
+	for _, seat := range hand.seats {
+		seatView := SeatView{
+			Bet:         hand.bets[seat],
+			Folded:      hand.folded[seat],
+			AllIn:       game.players[seat].Chips == 0 && !hand.folded[seat],
+			UpCards:     hand.up[seat],
+			HiddenCards: len(hand.down[seat]),
+		}
+		if seat == viewer {
+			seatView.HoleCards = hand.down[seat]
+		} else if shown, ok := hand.record.Shown[seat]; ok {
+			seatView.HoleCards = shown
+		}
+		view.Seats[seat] = seatView
+	}
+
+	if !hand.over && viewer == hand.toAct {
+		options := game.actionOptions(viewer)
+		view.Options = &options
+	}
+	if hand.over {
+		view.Results = hand.record.Pots
+	}
+	if deadline, ok := game.ActionDeadline(); ok {
+		view.Deadline = &deadline
+	}
+
+	return view, true
+}
+
+// ActionOptions gets the legal actions for the player in seat. Returns false if it is not their turn
+func (game *Game) ActionOptions(seat int) (ActionOptions, bool) {
+	if !game.HandInProgress() || game.hand.toAct != seat {
+		return ActionOptions{}, false
+	}
+	return game.actionOptions(seat), true
+}
+
+// Act takes an action for the player in seat, returning an error if it is not their turn or
+// the action is not allowed
+func (game *Game) Act(seat int, action Action) error {
+	if !game.HandInProgress() {
+		return fmt.Errorf("No hand in progress")
+	}
+
+	hand := game.hand
+	if seat != hand.toAct {
+		return fmt.Errorf("It is not seat %d's turn, waiting on seat %d", seat, hand.toAct)
+	}
+
+	options := game.actionOptions(seat)
+	recorded := Action{Type: action.Type}
+	switch action.Type {
+	case Fold:
+		hand.folded[seat] = true
+	case Check:
+		if !options.CanCheck {
+			return errorOf(ErrInvalidAction, "Cannot check when facing a bet of %d", options.ToCall)
+		}
+	case Call:
+		if options.ToCall == 0 {
+			return errorOf(ErrInvalidAction, "Nothing to call, check instead")
+		}
+		game.putChips(seat, options.ToCall)
+		recorded.Amount = hand.bets[seat]
+	case Bet, Raise:
+		if action.Type == Bet && hand.currentBet > 0 {
+			return errorOf(ErrInvalidAction, "There is already a bet of %d, raise instead", hand.currentBet)
+		}
+		if action.Type == Raise && hand.currentBet == 0 {
+			return errorOf(ErrInvalidAction, "There is no bet to raise, bet instead")
+		}
+		if !options.CanRaise {
+			return errorOf(ErrInvalidAction, "Cannot %s right now", action.Type)
+		}
+		if action.Amount < options.MinRaiseTo || action.Amount > options.MaxRaiseTo {
+			return errorOf(ErrInvalidAction, "Must %s to between %d and %d", action.Type, options.MinRaiseTo, options.MaxRaiseTo)
+		}
+		game.raiseTo(seat, action.Amount)
+		recorded.Amount = action.Amount
+	default:
+		return errorOf(ErrInvalidAction, "Cannot take action %s", action.Type)
+	}
+
+	game.useTime(seat)
+	hand.needsToAct[seat] = false
+	hand.raiseOpen[seat] = false
+	hand.record.Actions = append(hand.record.Actions, ActionRecord{Seat: seat, Street: hand.street, Action: recorded})
+	game.continueHand(indexOf(hand.order, seat) + 1)
+	return nil
+}
+
+// deal starts a new hand, dealing the hole cards and starting the first betting round
+func (game *Game) deal(bombPot bool) {
+	hand := &handState{
+		gameType:    game.current,
+		bombPot:     bombPot,
+		rules:       rulesFor(game.current.Variant),
+		down:        make(map[int][]Card),
+		up:          make(map[int][]Card),
+		contributed: make(map[int]int),
+		folded:      make(map[int]bool),
+		needsToAct:  make(map[int]bool),
+		raiseOpen:   make(map[int]bool),
+		record: HandRecord{
+			Number:  game.handsPlayed,
+			Game:    game.current,
+			Button:  game.button,
+			BombPot: bombPot,
+			Shown:   make(map[int][]Card),
+		},
+	}
+	game.hand = hand
+
+	for i := 1; i <= game.tableSize; i++ {
+		seat := (game.button + i) % game.tableSize
+		if player, ok := game.players[seat]; ok && player.dealtIn() {
+			hand.seats = append(hand.seats, seat)
+		}
+	}
+
+	game.deck.Shuffle()
+	for i := 0; i < hand.rules.holeCards; i++ {
+		for _, seat := range hand.seats {
+			hand.down[seat] = append(hand.down[seat], game.dealCard())
+		}
+	}
+
+	game.startStreet()
+}
+
+func (game *Game) dealCard() Card {
+	card, err := game.deck.DealCard()
+	if err != nil {
+		panic("Deck ran out of cards in the middle of a hand")
+	}
+	return card
+}
+
+// startStreet deals the cards for the current street and starts its betting round
+func (game *Game) startStreet() {
+	hand := game.hand
+	deal := hand.rules.streets[hand.street]
+	live := hand.liveSeats()
+
+	if perPlayer := deal.up + deal.down; perPlayer > 0 && game.deck.Len() < perPlayer*len(live) {
+		// In stud there may not be enough cards for everyone, so one card is shared by all
+		hand.board = append(hand.board, game.dealCard())
+	} else {
+		for i := 0; i < deal.up; i++ {
+			for _, seat := range live {
+				hand.up[seat] = append(hand.up[seat], game.dealCard())
+			}
+		}
+		for i := 0; i < deal.down; i++ {
+			for _, seat := range live {
+				hand.down[seat] = append(hand.down[seat], game.dealCard())
+			}
+		}
+	}
+	for i := 0; i < deal.board; i++ {
+		hand.board = append(hand.board, game.dealCard())
+	}
+
+	hand.bets = make(map[int]int)
+	hand.order = hand.seats
+	hand.currentBet = 0
+	hand.lastRaise = game.bigBlind()
+	hand.raises = 0
+	for _, seat := range live {
+		hand.needsToAct[seat] = game.players[seat].Chips > 0
+		hand.raiseOpen[seat] = hand.needsToAct[seat]
+	}
+
+	first := 0
+	if hand.street == 0 {
+		first = game.postForcedBets()
+	} else if hand.rules.stud {
+		first = indexOf(hand.order, hand.bestShowing())
+	}
+
+	// With at most one player able to bet, the only thing left to do is call a bet
+	canAct := 0
+	for _, seat := range live {
+		if game.players[seat].Chips > 0 {
+			canAct++
+		}
+	}
+	if canAct < 2 {
+		for _, seat := range live {
+			hand.needsToAct[seat] = hand.needsToAct[seat] && hand.bets[seat] < hand.currentBet
+		}
+	}
+
+	game.continueHand(first)
+}
+
+// postForcedBets posts the antes, blinds and straddle, or the bring in for stud. Returns the
+// index of the first player to act
+func (game *Game) postForcedBets() int {
+	hand := game.hand
+	blinds := game.blinds
+	straddler := game.straddler
+	game.straddler = -1
+
+	if hand.bombPot {
+		game.postBombPot()
+		return 0
+	}
+
+	if !blinds.BigBlindAnte || hand.rules.stud {
+		for _, seat := range hand.seats {
+			game.postAnte(seat, blinds.Ante)
+		}
+	}
+
+	if hand.rules.stud {
+		bringIn := hand.bringIn()
+		game.post(bringIn, game.smallBlind())
+		return indexOf(hand.order, bringIn) + 1
+	}
+
+	smallBlind, bigBlind := 0, 1
+	if len(hand.seats) == 2 {
+		// Heads up the button posts the small blind
+		smallBlind, bigBlind = 1, 0
+	}
+	game.post(hand.seats[smallBlind], game.smallBlind())
+	game.post(hand.seats[bigBlind], game.bigBlind())
+	game.markMissedBlinds(game.button, hand.seats[bigBlind])
+	game.postOwedBlinds(hand.seats[smallBlind], hand.seats[bigBlind])
+
+	// The big blind's own blind comes before the ante if they can't cover both
+	if blinds.BigBlindAnte {
+		game.postAnte(hand.seats[bigBlind], blinds.Ante)
+	}
+
+	// The big blind counts as the first bet when limiting raises
+	hand.raises = 1
+
+	if straddler == -1 || hand.gameType.Betting == FixedLimit || !game.canStraddle(straddler, game.button) {
+		return bigBlind + 1
+	}
+
+	// The straddle acts like another big blind, so it sets the size of the next raise
+	game.post(straddler, 2*game.bigBlind())
+	hand.lastRaise = hand.bets[straddler]
+	hand.raises = 2
+
+	button := hand.seats[len(hand.seats)-1]
+	if straddler == button && straddler != hand.seats[2] {
+		order := append([]int{}, hand.seats[2:len(hand.seats)-1]...)
+		order = append(order, hand.seats[:2]...)
+		hand.order = append(order, button)
+		return 0
+	}
+
+	return indexOf(hand.order, straddler) + 1
+}
+
+func (game *Game) post(seat int, amount int) {
+	hand := game.hand
+	if chips := game.players[seat].Chips; amount > chips {
+		amount = chips
+	}
+
+	game.putChips(seat, amount)
+	if hand.bets[seat] > hand.currentBet {
+		hand.currentBet = hand.bets[seat]
+	}
+	hand.record.Actions = append(hand.record.Actions, ActionRecord{
+		Seat:   seat,
+		Street: hand.street,
+		Action: Action{Type: Post, Amount: hand.bets[seat]},
+	})
+}
+
+// putChips moves chips from a player's stack into their bet
+func (game *Game) putChips(seat int, amount int) {
+	game.addToPot(seat, amount)
+	game.hand.bets[seat] += amount
+}
+
+// postAnte puts dead money in the pot, which does not count towards the player's bet
+func (game *Game) postAnte(seat int, amount int) {
+	if chips := game.players[seat].Chips; amount > chips {
+		amount = chips
+	}
+	if amount == 0 {
+		return
+	}
+
+	game.addToPot(seat, amount)
+	game.hand.record.Actions = append(game.hand.record.Actions, ActionRecord{
+		Seat:   seat,
+		Street: game.hand.street,
+		Action: Action{Type: Ante, Amount: amount},
+	})
+}
+
+func (game *Game) addToPot(seat int, amount int) {
+	hand := game.hand
+	game.adjustChips(seat, -amount)
+	hand.contributed[seat] += amount
+
+	if game.players[seat].Chips == 0 {
+		hand.needsToAct[seat] = false
+		hand.raiseOpen[seat] = false
+	}
+}
+
+func (game *Game) raiseTo(seat int, amount int) {
+	hand := game.hand
+	fullRaiseTo, _ := game.raiseBounds(seat)
+	raiseSize := amount - hand.currentBet
+
+	game.putChips(seat, amount-hand.bets[seat])
+	hand.currentBet = amount
+
+	full := amount >= fullRaiseTo
+	if full {
+		hand.raises++
+		if raiseSize > hand.lastRaise {
+			hand.lastRaise = raiseSize
+		}
+	}
+
+	for _, other := range hand.liveSeats() {
+		if other == seat || game.players[other].Chips == 0 {
+			continue
+		}
+		hand.needsToAct[other] = true
+		hand.raiseOpen[other] = hand.raiseOpen[other] || full
+	}
+}
+
+// raiseBounds gets the smallest full raise and the largest allowed raise for seat, without
+// looking at their stack
+func (game *Game) raiseBounds(seat int) (int, int) {
+	hand := game.hand
+	bigBetRound := hand.street >= len(hand.rules.streets)/2
+	return raiseBounds(hand.gameType.Betting, game.limits, game.bigBlind(), hand.currentBet, hand.lastRaise,
+		hand.bets[seat], hand.pot(), hand.raises, bigBetRound)
+}
+
+func (game *Game) actionOptions(seat int) ActionOptions {
+	hand := game.hand
+	chips := game.players[seat].Chips
+	stack := hand.bets[seat] + chips
+
+	toCall := hand.currentBet - hand.bets[seat]
+	if toCall > chips {
+		toCall = chips
+	}
+
+	options := ActionOptions{Seat: seat, ToCall: toCall, CanCheck: toCall == 0}
+
+	minRaiseTo, maxRaiseTo := game.raiseBounds(seat)
+	if maxRaiseTo == -1 || maxRaiseTo > stack {
+		maxRaiseTo = stack
+	}
+	if minRaiseTo > stack {
+		// Players can always go all in, even if it is not a full raise
+		minRaiseTo = stack
+	}
+
+	capped := hand.gameType.Betting == FixedLimit && hand.raises >= game.limits.RaiseCap
+	if hand.raiseOpen[seat] && stack > hand.currentBet && !capped {
+		options.CanRaise = true
+		options.MinRaiseTo = minRaiseTo
+		options.MaxRaiseTo = maxRaiseTo
+	}
+
+	return options
+}
+
+// continueHand finds the next player to act starting from the player at index, moving on
+// to the next street or showdown when the betting round is over
+func (game *Game) continueHand(index int) {
+	hand := game.hand
+	if len(hand.liveSeats()) == 1 {
+		game.awardPots()
+		return
+	}
+
+	for i := 0; i < len(hand.order); i++ {
+		seat := hand.order[(index+i)%len(hand.order)]
+		if hand.needsToAct[seat] {
+			hand.toAct = seat
+			hand.turnStarted = game.clock.Now()
+			return
+		}
+	}
+
+	hand.toAct = -1
+	hand.street++
+	if hand.street == len(hand.rules.streets) {
+		game.awardPots()
+		return
+	}
+
+	if game.needsRunChoice() {
+		hand.choosingRuns = true
+		hand.runVotes = make(map[int]int)
+		hand.turnStarted = game.clock.Now()
+		return
+	}
+
+	game.startStreet()
+}
+
+type pot struct {
+	amount   int
+	eligible []int
+}
+
+// pots splits everything contributed into the main pot and side pots
+func (hand *handState) pots() []pot {
+	live := hand.liveSeats()
+	levels := make([]int, 0)
+	for _, seat := range live {
+		if amount := hand.contributed[seat]; amount > 0 {
+			levels = append(levels, amount)
+		}
+	}
+	sort.Ints(levels)
+
+	result := make([]pot, 0)
+	assigned := 0
+	previous := 0
+	for _, level := range levels {
+		if level == previous {
+			continue
+		}
+
+		amount := 0
+		for _, contributed := range hand.contributed {
+			amount += minInt(contributed, level) - minInt(contributed, previous)
+		}
+
+		eligible := make([]int, 0)
+		for _, seat := range live {
+			if hand.contributed[seat] >= level {
+				eligible = append(eligible, seat)
+			}
+		}
+
+		result = append(result, pot{amount: amount, eligible: eligible})
+		assigned += amount
+		previous = level
+	}
+
+	// Players who folded may have put in more than anyone left in the hand
+	if leftover := hand.pot() - assigned; leftover > 0 {
+		if len(result) == 0 {
+			result = append(result, pot{eligible: live})
+		}
+		result[len(result)-1].amount += leftover
+	}
+
+	return result
+}
+
+// awardPots splits the pots between the winners, showing down if more than one player is left
+func (game *Game) awardPots() {
+	hand := game.hand
+	wilds := game.deck.Options().WildValues
+	live := hand.liveSeats()
+
+	if len(live) > 1 {
+		for _, seat := range live {
+			hand.record.Shown[seat] = hand.down[seat]
+		}
+	}
+
+	// When the board is run more than once, each pot is split evenly between the boards
+	boards := hand.boards
+	if len(boards) == 0 {
+		boards = [][]Card{hand.board}
+	}
+
+	for _, pot := range hand.pots() {
+		for run, board := range boards {
+			amount := pot.amount / len(boards)
+			if run == 0 {
+				amount += pot.amount % len(boards)
+			}
+
+			rake := game.takeRake(amount, hand.record.Rake)
+			hand.record.Rake += rake
+			result := PotResult{Amount: amount, Rake: rake, Eligible: pot.eligible, Run: run, Winnings: make(map[int]int)}
+			hand.splitBetweenWinners(amount-rake, pot.eligible, board, wilds, result.Winnings)
+
+			for seat, won := range result.Winnings {
+				game.adjustChips(seat, won)
+			}
+			hand.record.Pots = append(hand.record.Pots, result)
+		}
+	}
+
+	hand.over = true
+	hand.ended = game.clock.Now()
+	hand.toAct = -1
+	hand.record.Board = hand.board
+	if len(hand.boards) > 1 {
+		hand.record.Boards = hand.boards
+	}
+	game.rakeCollected += hand.record.Rake
+	game.history = append(game.history, hand.record)
+}
+
+// splitBetweenWinners awards amount to the best hands of the eligible players using board
+func (hand *handState) splitBetweenWinners(amount int, eligible []int, board []Card, wilds []CardValue,
+	winnings map[int]int) {
+	switch {
+	case len(eligible) == 1:
+		winnings[eligible[0]] += amount
+	case hand.rules.lowball:
+		splitPot(amount, hand.lowWinners(eligible, board, wilds, false), winnings)
+	case hand.rules.hiLo:
+		lowWinners := hand.lowWinners(eligible, board, wilds, true)
+		if len(lowWinners) == 0 {
+			splitPot(amount, hand.highWinners(eligible, board, wilds), winnings)
+		} else {
+			// Any odd chip goes to the high hand
+			splitPot(amount-amount/2, hand.highWinners(eligible, board, wilds), winnings)
+			splitPot(amount/2, lowWinners, winnings)
+		}
+	default:
+		splitPot(amount, hand.highWinners(eligible, board, wilds), winnings)
+	}
+}
+
+// splitPot divides amount between the winners, with odd chips going to the earliest winners
+func splitPot(amount int, winners []int, winnings map[int]int) {
+	share := amount / len(winners)
+	remainder := amount % len(winners)
+	for i, seat := range winners {
+		winnings[seat] += share
+		if i < remainder {
+			winnings[seat]++
+		}
+	}
+}
+
+func (hand *handState) highWinners(eligible []int, board []Card, wilds []CardValue) []int {
+	winners := make([]int, 0)
+	var best Hand
+	for _, seat := range eligible {
+		solved := hand.highHand(seat, board, wilds)
+		comparison := -1
+		if best != nil {
+			comparison = CompareHands(solved, best)
+		}
+
+		if comparison < 0 {
+			best = solved
+			winners = []int{seat}
+		} else if comparison == 0 {
+			winners = append(winners, seat)
+		}
+	}
+	return winners
+}
+
+// lowWinners finds who has the best low hand, with qualify only counting eight or better
+func (hand *handState) lowWinners(eligible []int, board []Card, wilds []CardValue, qualify bool) []int {
+	winners := make([]int, 0)
+	var best *LowHand
+	for _, seat := range eligible {
+		solved, ok := hand.lowHand(seat, board, wilds)
+		if !ok || (qualify && !solved.Qualifies(lowQualifier)) {
+			continue
+		}
+
+		comparison := -1
+		if best != nil {
+			comparison = CompareLowHands(solved, *best)
+		}
+
+		if comparison < 0 {
+			best = &solved
+			winners = []int{seat}
+		} else if comparison == 0 {
+			winners = append(winners, seat)
+		}
+	}
+	return winners
+}
+
+func (hand *handState) highHand(seat int, board []Card, wilds []CardValue) Hand {
+	var best Hand
+	for _, cards := range hand.playableCards(seat, board) {
+		solved, err := SolveWildHand(cards, wilds)
+		if err != nil {
+			panic(err)
+		}
+		if best == nil || CompareHands(solved, best) < 0 {
+			best = solved
+		}
+	}
+	return best
+}
+
+func (hand *handState) lowHand(seat int, board []Card, wilds []CardValue) (LowHand, bool) {
+	var best *LowHand
+	for _, cards := range hand.playableCards(seat, board) {
+		solved, err := SolveLowHand(cards, wilds)
+		if err != nil {
+			panic(err)
+		}
+		if best == nil || CompareLowHands(solved, *best) < 0 {
+			best = &solved
+		}
+	}
+
+	if best == nil {
+		return LowHand{}, false
+	}
+	return *best, true
+}
+
+// playableCards gets the sets of cards a player can make a hand from. In Omaha a hand must
+// use exactly two hole cards and three from the board
+func (hand *handState) playableCards(seat int, board []Card) [][]Card {
+	if !hand.rules.omaha {
+		cards := append([]Card{}, hand.down[seat]...)
+		cards = append(cards, hand.up[seat]...)
+		return [][]Card{append(cards, board...)}
+	}
+
+	result := make([][]Card, 0)
+	for _, hole := range cardCombinations(hand.down[seat], 2) {
+		for _, fromBoard := range cardCombinations(board, 3) {
+			result = append(result, append(append([]Card{}, hole...), fromBoard...))
+		}
+	}
+	return result
+}
+
+// bringIn finds who is forced to start the betting in stud. It is the lowest up card, or the
+// highest in Razz, with ties broken by suit
+func (hand *handState) bringIn() int {
+	// Suits are ordered from spades down, so the lowest suit is the largest Suit
+	cardRank := func(card Card) int {
+		if hand.rules.lowball {
+			return -lowValue(card.value)*NumSuits + int(card.suit)
+		}
+		return int(card.value)*NumSuits + (NumSuits - 1 - int(card.suit))
+	}
+
+	bringIn := hand.seats[0]
+	for _, seat := range hand.seats {
+		if cardRank(hand.up[seat][0]) < cardRank(hand.up[bringIn][0]) {
+			bringIn = seat
+		}
+	}
+	return bringIn
+}
+
+// bestShowing finds who acts first on the later streets of stud, which is whoever has the
+// best hand showing. In Razz that is the lowest hand
+func (hand *handState) bestShowing() int {
+	best := -1
+	var bestKey []int
+	for _, seat := range hand.seats {
+		if hand.folded[seat] {
+			continue
+		}
+
+		key := showingKey(hand.up[seat], hand.rules.lowball)
+		if best == -1 || compareLowKeys(key, bestKey) < 0 {
+			best = seat
+			bestKey = key
+		}
+	}
+	return best
+}
+
+// showingKey ranks the up cards in a stud hand, where the lowest key is the best. Only pairs,
+// trips and quads count since there are not enough cards for straights and flushes
+func showingKey(cards []Card, lowball bool) []int {
+	counts := make(map[int]int)
+	for _, card := range cards {
+		value := int(card.value)
+		if lowball {
+			value = lowValue(card.value)
+		}
+		counts[value]++
+	}
+
+	values := make([]int, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] > values[j]
+	})
+
+	// Hands are ranked by how many of each value they have, so trips beat two pair, and then by
+	// the values. For high hands more of a kind and higher values are better. Low hands are the
+	// opposite
+	key := make([]int, 0, 2*len(values))
+	for _, value := range values {
+		if lowball {
+			key = append(key, counts[value])
+		} else {
+			key = append(key, -counts[value])
+		}
+	}
+	for _, value := range values {
+		if lowball {
+			key = append(key, value)
+		} else {
+			key = append(key, -value)
+		}
+	}
+	return key
+}
+
+func (hand *handState) liveSeats() []int {
+	live := make([]int, 0, len(hand.seats))
+	for _, seat := range hand.seats {
+		if !hand.folded[seat] {
+			live = append(live, seat)
+		}
+	}
+	return live
+}
+
+func indexOf(seats []int, seat int) int {
+	for i, s := range seats {
+		if s == seat {
+			return i
+		}
+	}
+	return -1
+}
+
+func (hand *handState) pot() int {
+	total := 0
+	for _, amount := range hand.contributed {
+		total += amount
+	}
+	return total
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,444 @@
+package poker
+
+import (
+	"errors"
+	"testing"
+)
+
+// stackDeck makes the next cards dealt be cards, in order. Stacked cards which were already
+// dealt are swapped for undealt ones, so the deck can still be shuffled
+func stackDeck(game *Game, cards []Card) {
+	isStacked := func(card Card) bool {
+		for _, c := range cards {
+			if c == card {
+				return true
+			}
+		}
+		return false
+	}
+
+	remaining := make([]Card, 0, len(game.deck.cards))
+	for _, card := range game.deck.cards {
+		if !isStacked(card) {
+			remaining = append(remaining, card)
+		}
+	}
+
+	dealt := make([]Card, 0, len(game.deck.dealt))
+	for _, card := range game.deck.dealt {
+		if !isStacked(card) {
+			dealt = append(dealt, card)
+		} else {
+			dealt = append(dealt, remaining[0])
+			remaining = remaining[1:]
+		}
+	}
+
+	for i := len(cards) - 1; i >= 0; i-- {
+		remaining = append(remaining, cards[i])
+	}
+	game.deck.cards = remaining
+	game.deck.dealt = dealt
+}
+
+func setChips(game *Game, seat int, chips int) {
+	player := game.players[seat]
+	player.Chips = chips
+	game.players[seat] = player
+}
+
+func mustAct(t *testing.T, game *Game, seat int, action Action) {
+	t.Helper()
+	if err := game.Act(seat, action); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandBlindsAndOrder(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 1, 4, 6)
+	game.StartHand()
+
+	view, _ := game.HandView(-1)
+	if view.Button != 1 || view.Seats[4].Bet != 5 || view.Seats[6].Bet != 10 {
+		t.Error("Small blind and big blind should be left of the button")
+	}
+	if view.ToAct != 1 || view.Pot != 15 {
+		t.Error()
+	}
+	if len(view.Seats[4].HoleCards) != 0 {
+		t.Error("Public view cannot see hole cards")
+	}
+
+	mine, _ := game.HandView(1)
+	if len(mine.Seats[1].HoleCards) != 2 || mine.Options == nil || mine.Options.ToCall != 10 {
+		t.Error()
+	}
+
+	if err := game.Act(4, Action{Type: Call}); err == nil || errors.Is(err, ErrInvalidAction) {
+		t.Error("Cannot act out of turn", err)
+	}
+	mustAct(t, &game, 1, Action{Type: Call})
+	mustAct(t, &game, 4, Action{Type: Call})
+
+	options, _ := game.ActionOptions(6)
+	if !options.CanCheck || !options.CanRaise {
+		t.Error("Big blind gets the option")
+	}
+	mustAct(t, &game, 6, Action{Type: Check})
+
+	view, _ = game.HandView(-1)
+	if len(view.Board) != 3 || view.ToAct != 4 {
+		t.Error("First to act after the flop is left of the button")
+	}
+}
+
+func TestHandHeadsUpBlinds(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 2, 5)
+	game.StartHand()
+
+	view, _ := game.HandView(-1)
+	if view.Button != 2 || view.Seats[2].Bet != 5 || view.Seats[5].Bet != 10 || view.ToAct != 2 {
+		t.Error("Heads up the button is the small blind and acts first")
+	}
+
+	mustAct(t, &game, 2, Action{Type: Call})
+	mustAct(t, &game, 5, Action{Type: Check})
+
+	view, _ = game.HandView(-1)
+	if view.ToAct != 5 {
+		t.Error("Heads up the button acts last after the flop")
+	}
+}
+
+func TestHandFoldAwardsPot(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2)
+	game.StartHand()
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 30})
+	mustAct(t, &game, 1, Action{Type: Fold})
+	mustAct(t, &game, 2, Action{Type: Fold})
+
+	if game.HandInProgress() {
+		t.Error()
+	}
+	if game.players[0].Chips != 115 || game.players[1].Chips != 95 || game.players[2].Chips != 90 {
+		t.Error()
+	}
+
+	history := game.History()
+	if len(history) != 1 || history[0].Pots[0].Winnings[0] != 45 || len(history[0].Shown) != 0 {
+		t.Error()
+	}
+}
+
+func TestNoLimitBets(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2)
+	game.StartHand()
+
+	options, _ := game.ActionOptions(0)
+	if options.MinRaiseTo != 20 || options.MaxRaiseTo != 100 {
+		t.Error(options)
+	}
+	if err := game.Act(0, Action{Type: Raise, Amount: 15}); !errors.Is(err, ErrInvalidAction) {
+		t.Error("Raise needs to be at least the big blind", err)
+	}
+	if err := game.Act(0, Action{Type: Bet, Amount: 20}); err == nil {
+		t.Error("Blinds count as a bet")
+	}
+
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 35})
+	options, _ = game.ActionOptions(1)
+	if options.MinRaiseTo != 60 || options.ToCall != 30 {
+		t.Error("Min raise is the size of the last raise")
+	}
+}
+
+func TestPotLimitBets(t *testing.T) {
+	game := newTestGame(t, GameOptions{Betting: PotLimit}, 0, 1, 2)
+	setChips(&game, 0, 1000)
+	game.StartHand()
+
+	// Calling 10 makes the pot 25, so the raise is to 10 + 25
+	options, _ := game.ActionOptions(0)
+	if options.MaxRaiseTo != 35 {
+		t.Error(options)
+	}
+	if err := game.Act(0, Action{Type: Raise, Amount: 40}); err == nil {
+		t.Error()
+	}
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 35})
+
+	// Calling 30 makes the pot 80, so the raise is to 35 + 80
+	setChips(&game, 1, 1000)
+	options, _ = game.ActionOptions(1)
+	if options.MaxRaiseTo != 115 {
+		t.Error(options)
+	}
+}
+
+func TestFixedLimitBets(t *testing.T) {
+	game := newTestGame(t, GameOptions{Betting: FixedLimit}, 0, 1, 2)
+	game.StartHand()
+
+	options, _ := game.ActionOptions(0)
+	if options.MinRaiseTo != 20 || options.MaxRaiseTo != 20 {
+		t.Error(options)
+	}
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 20})
+	mustAct(t, &game, 1, Action{Type: Raise, Amount: 30})
+	mustAct(t, &game, 2, Action{Type: Raise, Amount: 40})
+
+	options, _ = game.ActionOptions(0)
+	if options.CanRaise {
+		t.Error("Betting is capped after a bet and three raises")
+	}
+	mustAct(t, &game, 0, Action{Type: Call})
+	mustAct(t, &game, 1, Action{Type: Call})
+
+	options, _ = game.ActionOptions(1)
+	if options.MinRaiseTo != 10 || options.MaxRaiseTo != 10 {
+		t.Error("Small bet on the flop")
+	}
+	mustAct(t, &game, 1, Action{Type: Check})
+	mustAct(t, &game, 2, Action{Type: Check})
+	mustAct(t, &game, 0, Action{Type: Check})
+
+	options, _ = game.ActionOptions(1)
+	if options.MinRaiseTo != 20 || options.MaxRaiseTo != 20 {
+		t.Error("Big bet on the turn")
+	}
+}
+
+func TestSpreadLimitBets(t *testing.T) {
+	if _, err := NewGameWithOptions(GameOptions{StarterChips: 100, BlindSize: 10, Betting: SpreadLimit}); err == nil {
+		t.Error("Spread limit needs a spread")
+	}
+
+	game := newTestGame(t, GameOptions{Betting: SpreadLimit, Limits: BettingLimits{SpreadMin: 10, SpreadMax: 30}}, 0, 1, 2)
+	game.StartHand()
+
+	options, _ := game.ActionOptions(0)
+	if options.MinRaiseTo != 20 || options.MaxRaiseTo != 40 {
+		t.Error(options)
+	}
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 35})
+
+	options, _ = game.ActionOptions(1)
+	if options.MinRaiseTo != 60 || options.MaxRaiseTo != 65 {
+		t.Error("Raise needs to be at least the last raise and within the spread")
+	}
+}
+
+func TestShortAllInDoesNotReopenBetting(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2)
+	setChips(&game, 0, 1000)
+	setChips(&game, 2, 1000)
+	setChips(&game, 1, 60)
+	game.StartHand()
+
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 40})
+	mustAct(t, &game, 1, Action{Type: Raise, Amount: 60})
+	mustAct(t, &game, 2, Action{Type: Call})
+
+	options, _ := game.ActionOptions(0)
+	if options.ToCall != 20 || options.CanRaise {
+		t.Error("All in for less than a raise only lets players call")
+	}
+}
+
+func TestShowdownSidePots(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2)
+	setChips(&game, 0, 50)
+	setChips(&game, 1, 100)
+	setChips(&game, 2, 100)
+	game.StartHand()
+
+	game.hand.down[0] = buildCards([]string{"AS", "AH"})
+	game.hand.down[1] = buildCards([]string{"KS", "KH"})
+	game.hand.down[2] = buildCards([]string{"QS", "QH"})
+	stackDeck(&game, buildCards([]string{"2C", "7D", "9C", "3H", "4D"}))
+
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 50})
+	mustAct(t, &game, 1, Action{Type: Raise, Amount: 100})
+	mustAct(t, &game, 2, Action{Type: Call})
+
+	if game.HandInProgress() {
+		t.Fatal("Everyone is all in so the board should run out")
+	}
+	if game.players[0].Chips != 150 || game.players[1].Chips != 100 || game.players[2].Chips != 0 {
+		t.Error(game.players)
+	}
+
+	record := game.History()[0]
+	if len(record.Pots) != 2 || record.Pots[0].Amount != 150 || record.Pots[1].Amount != 100 {
+		t.Error(record.Pots)
+	}
+	if len(record.Shown) != 3 || len(record.Board) != 5 {
+		t.Error()
+	}
+}
+
+func TestShowdownChop(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2)
+	game.StartHand()
+
+	game.hand.down[0] = buildCards([]string{"2S", "3H"})
+	game.hand.down[1] = buildCards([]string{"2D", "3C"})
+	game.hand.down[2] = buildCards([]string{"4S", "5H"})
+	stackDeck(&game, buildCards([]string{"AC", "KD", "QC", "JH", "TD"}))
+
+	mustAct(t, &game, 0, Action{Type: Call})
+	mustAct(t, &game, 1, Action{Type: Call})
+	mustAct(t, &game, 2, Action{Type: Check})
+	mustAct(t, &game, 1, Action{Type: Bet, Amount: 11})
+	mustAct(t, &game, 2, Action{Type: Call})
+	mustAct(t, &game, 0, Action{Type: Fold})
+	for game.HandInProgress() {
+		mustAct(t, &game, game.hand.toAct, Action{Type: Check})
+	}
+
+	// Both play the board, and the odd chip goes to the left of the button
+	winnings := game.History()[0].Pots[0].Winnings
+	if winnings[1] != 26 || winnings[2] != 26 || winnings[0] != 0 {
+		t.Error(winnings)
+	}
+}
+
+func TestOmahaUsesTwoHoleCards(t *testing.T) {
+	game := newTestGame(t, GameOptions{Betting: PotLimit, Rotation: Rotation{Games: []GameType{{Variant: Omaha, Betting: PotLimit}}}}, 0, 1)
+	game.StartHand()
+
+	if len(game.hand.down[0]) != 4 {
+		t.Fatal()
+	}
+	game.hand.down[0] = buildCards([]string{"AH", "2C", "3C", "4C"})
+	game.hand.down[1] = buildCards([]string{"KS", "KD", "8C", "9D"})
+	stackDeck(&game, buildCards([]string{"5H", "7H", "9H", "JH", "2D"}))
+
+	mustAct(t, &game, 0, Action{Type: Call})
+	for game.HandInProgress() {
+		mustAct(t, &game, game.hand.toAct, Action{Type: Check})
+	}
+
+	if game.History()[0].Pots[0].Winnings[1] != 20 {
+		t.Error("One heart in hand can't make a flush in Omaha")
+	}
+}
+
+func TestOmahaHiLoSplit(t *testing.T) {
+	game := newTestGame(t, GameOptions{Rotation: Rotation{Games: []GameType{{Variant: OmahaHiLo}}}}, 0, 1, 2)
+	game.StartHand()
+
+	game.hand.down[0] = buildCards([]string{"AH", "2C", "KS", "KD"})
+	game.hand.down[1] = buildCards([]string{"QS", "QD", "JC", "JD"})
+	game.hand.down[2] = buildCards([]string{"TS", "TD", "9C", "9D"})
+	stackDeck(&game, buildCards([]string{"KH", "4S", "5D", "8H", "QC"}))
+
+	mustAct(t, &game, 0, Action{Type: Call})
+	mustAct(t, &game, 1, Action{Type: Call})
+	mustAct(t, &game, 2, Action{Type: Check})
+	for game.HandInProgress() {
+		mustAct(t, &game, game.hand.toAct, Action{Type: Check})
+	}
+
+	winnings := game.History()[0].Pots[0].Winnings
+	if winnings[0] != 30 || winnings[1] != 0 {
+		t.Error("Trip kings and an ace to eight low should scoop", winnings)
+	}
+}
+
+func TestStudBringInAndRazz(t *testing.T) {
+	game := newTestGame(t, GameOptions{Rotation: Rotation{Games: []GameType{{Variant: Stud, Betting: FixedLimit}}}}, 0, 1, 2)
+	game.StartHand()
+
+	hand := game.hand
+	if len(hand.down[0]) != 2 || len(hand.up[0]) != 1 {
+		t.Fatal()
+	}
+
+	bringIn := hand.bringIn()
+	if hand.bets[bringIn] != 5 || hand.toAct != hand.seats[(indexOf(hand.seats, bringIn)+1)%3] {
+		t.Error("Lowest card brings in and the player to the left acts first")
+	}
+
+	options, _ := game.ActionOptions(hand.toAct)
+	if options.MinRaiseTo != 10 || options.ToCall != 5 {
+		t.Error("Raising the bring in completes it to the small bet", options)
+	}
+
+	hand.up[0] = buildCards([]string{"2C"})
+	hand.up[1] = buildCards([]string{"2S"})
+	hand.up[2] = buildCards([]string{"KD"})
+	if hand.bringIn() != 0 {
+		t.Error("Ties are broken by suit, with clubs lowest")
+	}
+
+	hand.rules = rulesFor(Razz)
+	if hand.bringIn() != 2 {
+		t.Error("Highest card brings in for Razz")
+	}
+
+	hand.up[0] = buildCards([]string{"2C", "2D"})
+	hand.up[1] = buildCards([]string{"AS", "KS"})
+	hand.up[2] = buildCards([]string{"5D", "7D"})
+	if hand.bestShowing() != 2 {
+		t.Error("Lowest hand showing acts first in Razz")
+	}
+	hand.rules = rulesFor(Stud)
+	if hand.bestShowing() != 0 {
+		t.Error("Pairs act first in Stud")
+	}
+
+	hand.up[0] = buildCards([]string{"2C", "2D", "2H", "AS"})
+	hand.up[1] = buildCards([]string{"AC", "AD", "KH", "KS"})
+	hand.up[2] = buildCards([]string{"QD", "JD", "9C", "8C"})
+	if hand.bestShowing() != 0 {
+		t.Error("Trips beat two pair", showingKey(hand.up[0], false), showingKey(hand.up[1], false))
+	}
+	hand.up[0] = buildCards([]string{"3C", "3D", "3H", "3S"})
+	hand.up[1] = buildCards([]string{"AC", "AD", "AH", "KS"})
+	hand.up[2] = buildCards([]string{"QD", "QS", "QC", "8C"})
+	if hand.bestShowing() != 0 {
+		t.Error("Quads beat trips")
+	}
+	quads, fullHouse := buildCards([]string{"3C", "3D", "3H", "3S", "2C"}), buildCards([]string{"AC", "AD", "AH", "KS", "KC"})
+	if compareLowKeys(showingKey(quads, false), showingKey(fullHouse, false)) >= 0 {
+		t.Error("Quads beat a full house")
+	}
+
+	// The one pair showing is worse than two different cards in Razz, however low the pair
+	hand.rules = rulesFor(Razz)
+	hand.up[0] = buildCards([]string{"AC", "AD", "2H"})
+	hand.up[1] = buildCards([]string{"KC", "QD", "JH"})
+	hand.up[2] = buildCards([]string{"KD", "KS", "QH"})
+	if hand.bestShowing() != 1 {
+		t.Error("Fewest pairs act first in Razz")
+	}
+}
+
+func TestStudPlaysToShowdown(t *testing.T) {
+	game := newTestGame(t, GameOptions{Rotation: Rotation{Games: []GameType{{Variant: StudHiLo, Betting: FixedLimit}}}}, 0, 1, 2, 3, 4, 5, 6, 7)
+	game.StartHand()
+
+	for game.HandInProgress() {
+		options, _ := game.ActionOptions(game.hand.toAct)
+		action := Action{Type: Check}
+		if options.ToCall > 0 {
+			action = Action{Type: Call}
+		}
+		mustAct(t, &game, game.hand.toAct, action)
+	}
+
+	// Eight players need 56 cards, so the last card is shared
+	record := game.History()[0]
+	if len(record.Board) != 1 || len(record.Shown) != 8 {
+		t.Error(record.Board)
+	}
+
+	total := 0
+	for _, player := range game.players {
+		total += player.Chips
+	}
+	if total != 800 {
+		t.Error("Chips should not be created or lost")
+	}
+}
@@ -0,0 +1,116 @@
+package poker
+
+import (
+	"fmt"
+)
+
+// MaxBoardRuns is the most times the rest of the board can be run
+const MaxBoardRuns int = 3
+
+// ChoosingRuns tells if the players who are all in are agreeing on how many times to run the
+// rest of the board
+func (game *Game) ChoosingRuns() bool {
+	return game.HandInProgress() && game.hand.choosingRuns
+}
+
+// MaxRuns gets the most times players can agree to run the rest of the board. 1 means the
+// board is never run more than once
+func (game *Game) MaxRuns() int {
+	if game.maxRuns < 1 {
+		return 1
+	}
+	return game.maxRuns
+}
+
+// ChooseRuns is a vote by the player in seat for how many times to run the rest of the board.
+// Once everyone in the hand has voted, the board is run the fewest times anyone voted for
+func (game *Game) ChooseRuns(seat int, runs int) error {
+	if !game.ChoosingRuns() {
+		return fmt.Errorf("Not waiting on players to choose how many times to run it")
+	}
+
+	hand := game.hand
+	if indexOf(hand.liveSeats(), seat) == -1 {
+		return fmt.Errorf("Seat %d is not in the hand", seat)
+	}
+	if runs < 1 || runs > game.MaxRuns() {
+		return fmt.Errorf("Can only run it between 1 and %d times", game.MaxRuns())
+	}
+	if runs > game.runsLeftInDeck() {
+		return fmt.Errorf("Only enough cards left in the deck to run it %d times", game.runsLeftInDeck())
+	}
+
+	hand.runVotes[seat] = runs
+	agreed := runs
+	for _, live := range hand.liveSeats() {
+		vote, ok := hand.runVotes[live]
+		if !ok {
+			return nil
+		}
+		agreed = minInt(agreed, vote)
+	}
+
+	hand.choosingRuns = false
+	if agreed == 1 {
+		game.startStreet()
+		return nil
+	}
+
+	game.runOut(agreed)
+	return nil
+}
+
+// needsRunChoice tells if betting is over with board cards still to come, so the players who
+// are all in can choose to run the rest of the board more than once
+func (game *Game) needsRunChoice() bool {
+	hand := game.hand
+	if game.MaxRuns() < 2 || hand.rules.stud || hand.runVotes != nil {
+		return false
+	}
+
+	canAct := 0
+	for _, seat := range hand.liveSeats() {
+		if game.players[seat].Chips > 0 {
+			canAct++
+		}
+	}
+	return canAct < 2 && game.runsLeftInDeck() > 1
+}
+
+// boardCardsLeft is how many board cards are still to come in the hand
+func (game *Game) boardCardsLeft() int {
+	remaining := 0
+	for _, deal := range game.hand.rules.streets[game.hand.street:] {
+		remaining += deal.board
+	}
+	return remaining
+}
+
+// runsLeftInDeck is how many times the rest of the board can be dealt from what is left in
+// the deck. A full table of Omaha leaves too few cards to run it many times
+func (game *Game) runsLeftInDeck() int {
+	remaining := game.boardCardsLeft()
+	if remaining == 0 {
+		return game.MaxRuns()
+	}
+	return game.deck.Len() / remaining
+}
+
+// runOut deals the rest of the board once for each run, and goes to showdown
+func (game *Game) runOut(runs int) {
+	hand := game.hand
+	remaining := game.boardCardsLeft()
+
+	dealt := hand.board
+	for run := 0; run < runs; run++ {
+		board := append([]Card{}, dealt...)
+		for i := 0; i < remaining; i++ {
+			board = append(board, game.dealCard())
+		}
+		hand.boards = append(hand.boards, board)
+	}
+
+	hand.board = hand.boards[0]
+	hand.street = len(hand.rules.streets)
+	game.awardPots()
+}
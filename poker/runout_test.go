@@ -0,0 +1,107 @@
+package poker
+
+import (
+	"testing"
+)
+
+func allInHeadsUp(t *testing.T, maxRuns int) Game {
+	game := newTestGame(t, GameOptions{MaxRuns: maxRuns}, 0, 1)
+	game.StartHand()
+
+	game.hand.down[0] = buildCards([]string{"AS", "AH"})
+	game.hand.down[1] = buildCards([]string{"KS", "KH"})
+	stackDeck(&game, buildCards([]string{"2C", "7D", "9C", "3H", "4D", "KD", "8C", "5S", "6H", "JC"}))
+
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 100})
+	mustAct(t, &game, 1, Action{Type: Call})
+	return game
+}
+
+func TestRunItTwice(t *testing.T) {
+	game := allInHeadsUp(t, 3)
+	if !game.ChoosingRuns() {
+		t.Fatal("Players all in should choose how many times to run it")
+	}
+	if err := game.Act(0, Action{Type: Check}); err == nil {
+		t.Error("No one acts while choosing runs")
+	}
+	if err := game.ChooseRuns(0, 4); err == nil {
+		t.Error("Can only run it up to the max")
+	}
+
+	if err := game.ChooseRuns(0, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := game.ChooseRuns(1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if game.HandInProgress() {
+		t.Fatal("The board should be run out")
+	}
+	if game.players[0].Chips != 100 || game.players[1].Chips != 100 {
+		t.Error("Each player wins one board", game.players)
+	}
+
+	record := game.History()[0]
+	if len(record.Boards) != 2 || len(record.Boards[1]) != 5 || record.Board[0] != record.Boards[0][0] {
+		t.Error(record.Boards)
+	}
+	if len(record.Pots) != 2 || record.Pots[1].Run != 1 || record.Pots[1].Winnings[1] != 100 {
+		t.Error(record.Pots)
+	}
+}
+
+func TestRunItOnce(t *testing.T) {
+	game := allInHeadsUp(t, 2)
+	game.ChooseRuns(0, 2)
+	if err := game.ChooseRuns(1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if game.HandInProgress() || game.players[0].Chips != 200 {
+		t.Error(game.players)
+	}
+	if record := game.History()[0]; len(record.Boards) != 0 || len(record.Pots) != 1 {
+		t.Error(record)
+	}
+}
+
+func TestNoRunsByDefault(t *testing.T) {
+	game := allInHeadsUp(t, 0)
+	if game.ChoosingRuns() || game.HandInProgress() {
+		t.Error("Board runs out once when the game doesn't allow running it more")
+	}
+}
+
+func TestRunItWithFullOmahaTable(t *testing.T) {
+	omaha := Rotation{Games: []GameType{{Variant: Omaha, Betting: NoLimit}}}
+	game := newTestGame(t, GameOptions{MaxRuns: 3, TableSize: 10, Rotation: omaha}, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	game.StartHand()
+	for game.HandInProgress() && !game.ChoosingRuns() {
+		options, _ := game.ActionOptions(game.hand.toAct)
+		action := Action{Type: Call}
+		if options.CanRaise {
+			action = Action{Type: Raise, Amount: options.MaxRaiseTo}
+		} else if options.ToCall == 0 {
+			action.Type = Check
+		}
+		mustAct(t, &game, game.hand.toAct, action)
+	}
+	if !game.ChoosingRuns() {
+		t.Fatal("Everyone is all in")
+	}
+
+	// 40 hole cards leave 12 in the deck, which is only enough for two boards
+	if err := game.ChooseRuns(0, 3); err == nil {
+		t.Error("Not enough cards to run it three times")
+	}
+	for seat := 0; seat < 10; seat++ {
+		if err := game.ChooseRuns(seat, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if game.HandInProgress() || len(game.History()[0].Boards) != 2 {
+		t.Error("The board should be run twice")
+	}
+}
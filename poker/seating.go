@@ -0,0 +1,171 @@
+package poker
+
+import (
+	"fmt"
+	"time"
+)
+
+// Reservation holds a seat for a player who hasn't sat down yet
+type Reservation struct {
+	Name    string    `json:"name"`
+	Expires time.Time `json:"expires"`
+	// Waitlist is set when the seat was offered to the next person on the waitlist
+	Waitlist bool `json:"waitlist"`
+	claim    int
+}
+
+// Claim is a getter for what sitting in or cancelling the reservation takes. Like a player's
+// secret, it should ONLY be given to whoever the seat is being held for
+func (reservation *Reservation) Claim() int {
+	return reservation.claim
+}
+
+// dealtIn tells if the player will be dealt into the next hand
+func (player *Player) dealtIn() bool {
+	return player.Chips > 0 && !player.SittingOut
+}
+
+// SitOut has the player in seat skip hands while keeping their seat and chips, starting with
+// the next hand. They owe a big blind when they come back if the blinds pass them while they
+// are away
+func (game *Game) SitOut(seat int) error {
+	player, ok := game.players[seat]
+	if !ok {
+		return fmt.Errorf("No player at seat %d", seat)
+	}
+	if player.SittingOut {
+		return fmt.Errorf("Seat %d is already sitting out", seat)
+	}
+
+	player.SittingOut = true
+	game.players[seat] = player
+	return nil
+}
+
+// SitIn brings back a player who was sitting out. Any big blind they owe is posted in the next
+// hand they are dealt into
+func (game *Game) SitIn(seat int) error {
+	player, ok := game.players[seat]
+	if !ok || !player.SittingOut {
+		return fmt.Errorf("Seat %d is not sitting out", seat)
+	}
+
+	player.SittingOut = false
+	game.players[seat] = player
+	return nil
+}
+
+// LeaveTable cashes out the player in seat and frees up their seat. They stay in the ledger
+func (game *Game) LeaveTable(seat int) (LedgerEntry, error) {
+	player, ok := game.players[seat]
+	if !ok {
+		return LedgerEntry{}, fmt.Errorf("No player at seat %d", seat)
+	}
+	if game.HandInProgress() && indexOf(game.hand.seats, seat) != -1 {
+		return LedgerEntry{}, fmt.Errorf("Seat %d can only leave between hands", seat)
+	}
+
+	entry := newLedgerEntry(seat, player, player.Chips)
+	entry.Left = true
+	game.departed = append(game.departed, entry)
+	delete(game.players, seat)
+	if game.straddler == seat {
+		game.straddler = -1
+	}
+	delete(game.bombPotVotes, seat)
+	return entry, nil
+}
+
+// ReserveSeat holds an empty seat for name until the timeout passes. Only someone with the
+// reservation's claim can sit there while it is reserved
+func (game *Game) ReserveSeat(seat int, name string, timeout time.Duration) (Reservation, error) {
+	if seat < 0 || seat >= game.tableSize {
+		return Reservation{}, errorOf(ErrInvalidSeat, "Invalid seat number %d", seat)
+	}
+	if _, ok := game.players[seat]; ok {
+		return Reservation{}, errorOf(ErrSeatTaken, "Already have player at seat %d", seat)
+	}
+	if _, ok := game.reservation(seat); ok {
+		return Reservation{}, errorOf(ErrSeatTaken, "Seat %d is already reserved", seat)
+	}
+	if timeout <= 0 {
+		return Reservation{}, fmt.Errorf("Reservation needs a positive timeout")
+	}
+
+	reservation := Reservation{Name: name, Expires: game.clock.Now().Add(timeout), claim: newSecret()}
+	game.reservations[seat] = reservation
+	return reservation, nil
+}
+
+// CancelReservation frees up a reserved seat, for whoever has the reservation's claim
+func (game *Game) CancelReservation(seat int, claim int) error {
+	reservation, ok := game.reservation(seat)
+	if !ok {
+		return fmt.Errorf("Seat %d is not reserved", seat)
+	}
+	if reservation.claim != claim {
+		return errorOf(ErrWrongClaim, "Wrong claim for the reservation at seat %d", seat)
+	}
+
+	delete(game.reservations, seat)
+	return nil
+}
+
+// Reservations gets the seats which are being held, and who for
+func (game *Game) Reservations() map[int]Reservation {
+	result := make(map[int]Reservation)
+	for seat := range game.reservations {
+		if reservation, ok := game.reservation(seat); ok {
+			result[seat] = reservation
+		}
+	}
+	return result
+}
+
+// reservation gets the reservation for seat, clearing it out if it has expired
+func (game *Game) reservation(seat int) (Reservation, bool) {
+	reservation, ok := game.reservations[seat]
+	if ok && !game.clock.Now().Before(reservation.Expires) {
+		delete(game.reservations, seat)
+		return Reservation{}, false
+	}
+	return reservation, ok
+}
+
+// markMissedBlinds has players sitting out owe a big blind when the blinds pass their seat,
+// which is when they are between the button and the big blind
+func (game *Game) markMissedBlinds(button int, bigBlind int) {
+	for i := 1; i < game.tableSize; i++ {
+		seat := (button + i) % game.tableSize
+		if seat == bigBlind {
+			return
+		}
+
+		if player, ok := game.players[seat]; ok && player.SittingOut {
+			player.OwesBlind = true
+			game.players[seat] = player
+		}
+	}
+}
+
+// postOwedBlinds has players back from sitting out post the big blind they owe. It is a live
+// bet, so they can check if no one raises
+func (game *Game) postOwedBlinds(smallBlind int, bigBlind int) {
+	for _, seat := range game.hand.seats {
+		player := game.players[seat]
+		if !player.OwesBlind {
+			continue
+		}
+
+		player.OwesBlind = false
+		game.players[seat] = player
+		if seat != smallBlind && seat != bigBlind {
+			game.post(seat, game.bigBlind())
+		}
+	}
+}
+
+// SitOut is not allowed in tournaments, since everyone is dealt in until they are knocked out
+func (tournament *Tournament) SitOut(seat int) error {
+	return fmt.Errorf("Players cannot sit out of a tournament")
+}
@@ -0,0 +1,106 @@
+package poker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSitOut(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2, 3, 4)
+	if err := game.SitOut(2); err != nil {
+		t.Fatal(err)
+	}
+	game.StartHand()
+
+	view, _ := game.HandView(-1)
+	if _, ok := view.Seats[2]; ok || view.Seats[3].Bet != 10 {
+		t.Error("Seat 2 is skipped, so seat 3 posts the big blind")
+	}
+	if !game.players[2].OwesBlind || game.players[2].Chips != 100 {
+		t.Error("Blinds passed seat 2 while they were away")
+	}
+
+	for game.HandInProgress() {
+		mustAct(t, &game, game.hand.toAct, Action{Type: Fold})
+	}
+	foldHand(t, &game)
+	if err := game.SitIn(2); err != nil {
+		t.Fatal(err)
+	}
+	game.StartHand()
+
+	// Seat 2 comes back on the button, and posts the big blind they owe on top of the blinds
+	view, _ = game.HandView(-1)
+	if view.Seats[3].Bet != 5 || view.Seats[4].Bet != 10 || view.Seats[2].Bet != 10 || game.players[2].OwesBlind {
+		t.Error(view.Seats)
+	}
+	if options, _ := game.ActionOptions(0); options.ToCall != 10 {
+		t.Error(options)
+	}
+}
+
+func TestLeaveTable(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2)
+	game.StartHand()
+	if _, err := game.LeaveTable(1); err == nil {
+		t.Error("Can't leave in the middle of a hand")
+	}
+	for game.HandInProgress() {
+		mustAct(t, &game, game.hand.toAct, Action{Type: Fold})
+	}
+
+	entry, err := game.LeaveTable(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Left || entry.CashOut != 95 || entry.Net != -5 {
+		t.Error(entry)
+	}
+	if empty := game.EmptySeats(); len(empty) != 6 || empty[0] != 1 {
+		t.Error("Seat 1 is open again", empty)
+	}
+	if ledger := game.Ledger(); len(ledger) != 3 || !ledger[2].Left {
+		t.Error("Players who left stay in the ledger", ledger)
+	}
+}
+
+func TestReserveSeat(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0)
+	now := time.Date(2020, time.January, 1, 19, 0, 0, 0, time.UTC)
+	game.clock = ClockFunc(func() time.Time { return now })
+
+	if _, err := game.ReserveSeat(0, "bob", time.Minute); err == nil {
+		t.Error("Seat is taken")
+	}
+	reservation, err := game.ReserveSeat(3, "bob", 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := game.AddPlayer("alice", 3); err == nil {
+		t.Error("Seat is reserved for bob")
+	}
+	if _, err := game.AddPlayer("bob", 3); err == nil {
+		t.Error("Sitting in a reserved seat takes the claim, not the name")
+	}
+	if len(game.EmptySeats()) != 6 {
+		t.Error()
+	}
+	if _, err := game.ClaimSeat("bob", 3, reservation.Claim()); err != nil {
+		t.Error(err)
+	}
+
+	reservation, _ = game.ReserveSeat(4, "carol", 5*time.Minute)
+	if err := game.CancelReservation(4, reservation.Claim()+1); !errors.Is(err, ErrWrongClaim) {
+		t.Error("Cancelling takes the claim", err)
+	}
+	if err := game.CancelReservation(4, reservation.Claim()); err != nil || len(game.Reservations()) != 0 {
+		t.Error(err)
+	}
+
+	game.ReserveSeat(4, "carol", 5*time.Minute)
+	now = now.Add(5 * time.Minute)
+	if _, err := game.AddPlayer("alice", 4); err != nil || len(game.Reservations()) != 0 {
+		t.Error("Reservation timed out")
+	}
+}
@@ -0,0 +1,169 @@
+package poker
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSitOutTimeouts is how many times in a row a player can run out of time before they
+// are sat out, unless the game sets its own limit
+const DefaultSitOutTimeouts int = 2
+
+// ShotClock is how long players have to act
+type ShotClock struct {
+	// Action is how long each player has for each action, 0 for no shot clock
+	Action time.Duration `json:"action"`
+	// TimeBank is extra time each player starts with, which they use once their action time
+	// runs out
+	TimeBank time.Duration `json:"timeBank"`
+	// Refill is added back to every time bank each hand, or each level in a tournament when
+	// RefillPerLevel is set. Time banks never hold more than TimeBank
+	Refill         time.Duration `json:"refill"`
+	RefillPerLevel bool          `json:"refillPerLevel"`
+	// SitOutAfter is how many timeouts in a row sit a player out, defaulting to
+	// DefaultSitOutTimeouts. Unlimited never sits anyone out
+	SitOutAfter int `json:"sitOutAfter"`
+}
+
+func (shotClock *ShotClock) validate() error {
+	if shotClock.Action < 0 || shotClock.TimeBank < 0 || shotClock.Refill < 0 || shotClock.SitOutAfter < Unlimited {
+		return fmt.Errorf("Shot clock cannot have a negative time or timeout limit")
+	}
+	if shotClock.Action == 0 && (shotClock.TimeBank > 0 || shotClock.Refill > 0) {
+		return fmt.Errorf("Time bank needs a shot clock")
+	}
+	return nil
+}
+
+func (shotClock *ShotClock) sitOutAfter() int {
+	if shotClock.SitOutAfter == 0 {
+		return DefaultSitOutTimeouts
+	}
+	return shotClock.SitOutAfter
+}
+
+// ShotClock gets how long players have to act
+func (game *Game) ShotClock() ShotClock {
+	return game.shotClock
+}
+
+// ActionDeadline gets when the player to act runs out of time, including their time bank.
+// Returns false when there is no shot clock or no one to act
+func (game *Game) ActionDeadline() (time.Time, bool) {
+	if game.shotClock.Action == 0 || !game.HandInProgress() || game.hand.toAct == -1 {
+		return time.Time{}, false
+	}
+
+	player := game.players[game.hand.toAct]
+	return game.hand.turnStarted.Add(game.shotClock.Action + player.TimeBank), true
+}
+
+// EnforceShotClock acts for the player whose time has run out, checking if they can and
+// folding if they can't. Players who time out too many times in a row are sat out starting
+// with the next hand. Players who run out of time to choose how many times to run it vote to
+// run it once. Returns the seat which timed out, or false if no one has
+func (game *Game) EnforceShotClock() (int, bool) {
+	if game.ChoosingRuns() {
+		return game.enforceRunVote()
+	}
+
+	deadline, ok := game.ActionDeadline()
+	if !ok || game.clock.Now().Before(deadline) {
+		return -1, false
+	}
+
+	seat := game.hand.toAct
+	action := Action{Type: Fold}
+	if options := game.actionOptions(seat); options.CanCheck {
+		action.Type = Check
+	}
+	timeouts := game.players[seat].timeouts + 1
+	if err := game.Act(seat, action); err != nil {
+		return -1, false
+	}
+
+	player := game.players[seat]
+	player.timeouts = timeouts
+	if limit := game.shotClock.sitOutAfter(); limit != Unlimited && timeouts >= limit {
+		player.SittingOut = true
+	}
+	game.players[seat] = player
+	return seat, true
+}
+
+// enforceRunVote votes to run it once for the first player whose time to vote has run out
+func (game *Game) enforceRunVote() (int, bool) {
+	if game.shotClock.Action == 0 {
+		return -1, false
+	}
+
+	hand := game.hand
+	for _, seat := range hand.liveSeats() {
+		if _, voted := hand.runVotes[seat]; voted {
+			continue
+		}
+		deadline := hand.turnStarted.Add(game.shotClock.Action + game.players[seat].TimeBank)
+		if game.clock.Now().Before(deadline) {
+			continue
+		}
+		if err := game.ChooseRuns(seat, 1); err != nil {
+			return -1, false
+		}
+		return seat, true
+	}
+	return -1, false
+}
+
+// useTime takes the time the player in seat went over their shot clock out of their time bank
+func (game *Game) useTime(seat int) {
+	if game.shotClock.Action == 0 {
+		return
+	}
+
+	player := game.players[seat]
+	over := game.clock.Now().Sub(game.hand.turnStarted) - game.shotClock.Action
+	if over > 0 {
+		player.TimeBank -= over
+	}
+	if player.TimeBank < 0 {
+		player.TimeBank = 0
+	}
+	player.timeouts = 0
+	game.players[seat] = player
+}
+
+// refillTimeBanks adds the refill back to everyone's time bank, up to a full time bank
+func (game *Game) refillTimeBanks() {
+	for seat, player := range game.players {
+		player.TimeBank += game.shotClock.Refill
+		if player.TimeBank > game.shotClock.TimeBank {
+			player.TimeBank = game.shotClock.TimeBank
+		}
+		game.players[seat] = player
+	}
+}
+
+// EnforceShotClock acts for the player whose time has run out, knocking out players once the
+// hand is over
+func (tournament *Tournament) EnforceShotClock() (int, bool) {
+	seat, ok := tournament.Game.EnforceShotClock()
+	tournament.finishHand()
+	return seat, ok
+}
+
+// EnforceShotClock acts for the entrant at table whose time has run out. Returns the id of the
+// entrant who timed out, or false if no one has
+func (tournament *MultiTableTournament) EnforceShotClock(table int) (int, bool) {
+	game, ok := tournament.tables[table]
+	if !ok {
+		return -1, false
+	}
+
+	seat, ok := game.EnforceShotClock()
+	if !ok {
+		return -1, false
+	}
+	id := tournament.entrantAt(table, seat)
+	tournament.finishHand(table)
+	return id, true
+}
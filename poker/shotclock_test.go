@@ -0,0 +1,144 @@
+package poker
+
+import (
+	"testing"
+	"time"
+)
+
+func newShotClockGame(t *testing.T, shotClock ShotClock, seats ...int) (Game, *time.Time) {
+	now := time.Date(2020, time.January, 1, 19, 0, 0, 0, time.UTC)
+	clock := ClockFunc(func() time.Time { return now })
+	return newTestGame(t, GameOptions{ShotClock: shotClock, Clock: clock}, seats...), &now
+}
+
+func TestShotClock(t *testing.T) {
+	shotClock := ShotClock{Action: 30 * time.Second, TimeBank: time.Minute, Refill: 10 * time.Second}
+	game, now := newShotClockGame(t, shotClock, 0, 1, 2)
+	game.StartHand()
+
+	*now = now.Add(30 * time.Second)
+	if _, ok := game.EnforceShotClock(); ok {
+		t.Error("Seat 0 still has their time bank")
+	}
+	if view, _ := game.HandView(-1); view.Deadline == nil || !view.Deadline.Equal(now.Add(time.Minute)) {
+		t.Error(view.Deadline)
+	}
+
+	// Seat 0 runs out of time facing the big blind, so they fold
+	*now = now.Add(time.Minute)
+	if seat, ok := game.EnforceShotClock(); !ok || seat != 0 || !game.hand.folded[0] || game.players[0].TimeBank != 0 {
+		t.Fatal(seat, ok)
+	}
+
+	*now = now.Add(40 * time.Second)
+	mustAct(t, &game, 1, Action{Type: Call})
+	if game.players[1].TimeBank != 50*time.Second {
+		t.Error(game.players[1].TimeBank)
+	}
+
+	// The big blind can check their option when they run out of time
+	*now = now.Add(90 * time.Second)
+	if seat, ok := game.EnforceShotClock(); !ok || seat != 2 || game.hand.folded[2] || game.hand.street != 1 {
+		t.Error(seat, ok)
+	}
+
+	for game.HandInProgress() {
+		mustAct(t, &game, game.hand.toAct, Action{Type: Check})
+	}
+	game.StartHand()
+	if game.players[0].TimeBank != 10*time.Second || game.players[1].TimeBank != time.Minute {
+		t.Error("Time banks are refilled every hand up to the full time bank")
+	}
+}
+
+func TestShotClockSitsOut(t *testing.T) {
+	game, now := newShotClockGame(t, ShotClock{Action: 30 * time.Second}, 0, 1, 2)
+	for hand := 0; hand < 2; hand++ {
+		game.StartHand()
+		for game.HandInProgress() {
+			*now = now.Add(30 * time.Second)
+			game.EnforceShotClock()
+		}
+	}
+
+	// Seat 1 timed out in both hands, while the others only had to act in one of them
+	if !game.players[1].SittingOut || game.players[0].SittingOut || game.players[2].SittingOut {
+		t.Error(game.players)
+	}
+
+	// Heads up the button acts first before the flop, so seat 0 acts every other hand
+	game, now = newShotClockGame(t, ShotClock{Action: 30 * time.Second}, 0, 1)
+	game.StartHand()
+	*now = now.Add(30 * time.Second)
+	if seat, ok := game.EnforceShotClock(); !ok || seat != 0 || game.players[0].timeouts != 1 {
+		t.Fatal(seat, ok)
+	}
+	foldHand(t, &game)
+	game.StartHand()
+	mustAct(t, &game, 0, Action{Type: Fold})
+	if game.players[0].SittingOut || game.players[0].timeouts != 0 {
+		t.Error("Acting in time resets the timeouts")
+	}
+}
+
+func TestTimeBankRefillPerLevel(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 19, 0, 0, 0, time.UTC)
+	tournament, err := NewTournament(TournamentOptions{
+		Game: GameOptions{
+			StarterChips: 100,
+			ShotClock:    ShotClock{Action: 30 * time.Second, TimeBank: time.Minute, Refill: time.Minute, RefillPerLevel: true},
+			Clock:        ClockFunc(func() time.Time { return now }),
+		},
+		Levels: []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}, Hands: 1}, {Blinds: Blinds{SmallBlind: 10, BigBlind: 20}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seat := 0; seat < 3; seat++ {
+		tournament.AddPlayer("player", seat)
+	}
+	if tournament.ShotClock().SitOutAfter != Unlimited {
+		t.Error("Players are never sat out of a tournament")
+	}
+
+	tournament.StartHand()
+	now = now.Add(time.Minute)
+	for tournament.HandInProgress() {
+		tournament.Act(tournament.hand.toAct, Action{Type: Fold})
+	}
+	if tournament.players[0].TimeBank != 30*time.Second {
+		t.Fatal(tournament.players[0].TimeBank)
+	}
+
+	tournament.StartHand()
+	if tournament.players[0].TimeBank != time.Minute {
+		t.Error("Time banks are refilled at the start of the level")
+	}
+}
+
+func TestRunVoteTimesOut(t *testing.T) {
+	game, now := newShotClockGame(t, ShotClock{Action: 30 * time.Second}, 0, 1)
+	game.maxRuns = 3
+	game.StartHand()
+	game.hand.down[0] = buildCards([]string{"AS", "AH"})
+	game.hand.down[1] = buildCards([]string{"KS", "KH"})
+	mustAct(t, &game, 0, Action{Type: Raise, Amount: 100})
+	mustAct(t, &game, 1, Action{Type: Call})
+	if !game.ChoosingRuns() {
+		t.Fatal("Players all in should choose how many times to run it")
+	}
+
+	game.ChooseRuns(0, 3)
+	if _, ok := game.EnforceShotClock(); ok {
+		t.Error("Seat 1 still has time to vote")
+	}
+
+	// Seat 1 never votes, so they vote to run it once
+	*now = now.Add(30 * time.Second)
+	if seat, ok := game.EnforceShotClock(); !ok || seat != 1 {
+		t.Fatal(seat, ok)
+	}
+	if game.HandInProgress() || len(game.History()[0].Boards) != 0 {
+		t.Error("The board is run once", game.History()[0].Boards)
+	}
+}
@@ -0,0 +1,133 @@
+package poker
+
+import (
+	"fmt"
+	"time"
+)
+
+// SpectatorTimeout is how long a spectator keeps watching after they last looked at the game.
+// Spectators who stop looking have to start spectating again
+const SpectatorTimeout = time.Minute
+
+// SpectatorRules are whether people without a seat can watch the game, and what they see
+type SpectatorRules struct {
+	// Disabled keeps everyone without a seat from watching
+	Disabled bool `json:"disabled"`
+	// ShowdownDelay is how long after a hand ends spectators wait to see the cards shown down,
+	// so they can't tell a player at the table what someone had before the table sees it
+	ShowdownDelay time.Duration `json:"showdownDelay"`
+}
+
+func (rules *SpectatorRules) validate() error {
+	if rules.ShowdownDelay < 0 {
+		return fmt.Errorf("Spectators cannot have a negative showdown delay")
+	}
+	return nil
+}
+
+// SpectatorRules gets the rules for watching the game
+func (game *Game) SpectatorRules() SpectatorRules {
+	return game.spectatorRules
+}
+
+// SetSpectatorRules changes the rules for watching the game, which take effect right away.
+// Disabling spectators sends away everyone watching
+func (game *Game) SetSpectatorRules(rules SpectatorRules) error {
+	if err := rules.validate(); err != nil {
+		return err
+	}
+	if rules.Disabled {
+		game.spectators = make(map[string]time.Time)
+	}
+	game.spectatorRules = rules
+	return nil
+}
+
+// AddSpectator has someone without a seat start watching the game. The token is how they are
+// known from then on, so it should be hard to guess
+func (game *Game) AddSpectator(token string) error {
+	if game.spectatorRules.Disabled {
+		return errorOf(ErrSpectatingDisabled, "Spectating is turned off for this game")
+	}
+	game.spectators[token] = game.clock.Now()
+	return nil
+}
+
+// Watch keeps the spectator with token watching the game for another SpectatorTimeout
+func (game *Game) Watch(token string) error {
+	if _, ok := game.spectator(token); !ok {
+		return errorOf(ErrNotSpectating, "Not spectating this game, or stopped looking for too long")
+	}
+	game.spectators[token] = game.clock.Now()
+	return nil
+}
+
+// Spectators is how many people are watching the game without a seat
+func (game *Game) Spectators() int {
+	count := 0
+	for token := range game.spectators {
+		if _, ok := game.spectator(token); ok {
+			count++
+		}
+	}
+	return count
+}
+
+// spectator gets when the spectator with token last looked at the game, clearing them out if
+// they stopped looking too long ago
+func (game *Game) spectator(token string) (time.Time, bool) {
+	lastSeen, ok := game.spectators[token]
+	if ok && !game.clock.Now().Before(lastSeen.Add(SpectatorTimeout)) {
+		delete(game.spectators, token)
+		return time.Time{}, false
+	}
+	return lastSeen, ok
+}
+
+// SpectatorView gets the current or last hand as someone watching without a seat sees it. They
+// never see hole cards, and only see the cards shown down and who won once the showdown delay
+// has passed. Returns false if no hand has been dealt yet
+func (game *Game) SpectatorView() (HandView, bool) {
+	view, ok := game.HandView(-1)
+	if !ok || !game.showdownHeldBack() {
+		return view, ok
+	}
+
+	for seat, seatView := range view.Seats {
+		seatView.HoleCards = nil
+		view.Seats[seat] = seatView
+	}
+	view.Results = nil
+	return view, true
+}
+
+// SpectatorPlayers gets the players as someone watching without a seat sees them. Until the
+// showdown delay has passed, stacks are from before the last hand's pots were awarded, so they
+// don't give away who won
+func (game *Game) SpectatorPlayers() map[int]Player {
+	if game.hand == nil || !game.hand.over || !game.showdownHeldBack() {
+		return game.players
+	}
+
+	players := make(map[int]Player, len(game.players))
+	for seat, player := range game.players {
+		players[seat] = player
+	}
+
+	for _, pot := range game.hand.record.Pots {
+		for seat, won := range pot.Winnings {
+			if player, ok := players[seat]; ok {
+				player.Chips -= won
+				players[seat] = player
+			}
+		}
+	}
+	return players
+}
+
+// showdownHeldBack tells if spectators still have to wait to see how the current or last hand
+// ended
+func (game *Game) showdownHeldBack() bool {
+	hand := game.hand
+	return hand != nil && (!hand.over || game.clock.Now().Before(hand.ended.Add(game.spectatorRules.ShowdownDelay)))
+}
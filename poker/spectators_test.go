@@ -0,0 +1,95 @@
+package poker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSpectatorView(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 19, 0, 0, 0, time.UTC)
+	clock := ClockFunc(func() time.Time { return now })
+	spectators := SpectatorRules{ShowdownDelay: 30 * time.Second}
+	game := newTestGame(t, GameOptions{Spectators: spectators, Clock: clock}, 0, 1, 2)
+	if _, ok := game.SpectatorView(); ok {
+		t.Error("No hand has been dealt")
+	}
+
+	game.StartHand()
+	view, _ := game.SpectatorView()
+	for seat, seatView := range view.Seats {
+		if len(seatView.HoleCards) != 0 || seatView.HiddenCards != 2 {
+			t.Error("Spectators can't see hole cards", seat, seatView)
+		}
+	}
+	for game.HandInProgress() {
+		action := Action{Type: Check}
+		if options, _ := game.ActionOptions(game.hand.toAct); options.ToCall > 0 {
+			action.Type = Call
+		}
+		mustAct(t, &game, game.hand.toAct, action)
+	}
+
+	now = now.Add(29 * time.Second)
+	if view, _ := game.SpectatorView(); !view.Over || len(view.Seats[0].HoleCards) != 0 || len(view.Results) != 0 {
+		t.Error("Showdown is held back from spectators", view)
+	}
+	// Everyone put in 10, so their stacks are the same until the pot is shown going to someone
+	for seat, player := range game.SpectatorPlayers() {
+		if player.Chips != 90 {
+			t.Error("Stacks don't give away who won", seat, player.Chips)
+		}
+	}
+	if view, _ := game.HandView(-1); len(view.Seats[0].HoleCards) != 2 || len(view.Results) == 0 {
+		t.Error("The table sees the showdown right away")
+	}
+	now = now.Add(time.Second)
+	if view, _ := game.SpectatorView(); len(view.Seats[0].HoleCards) != 2 || len(view.Results) == 0 {
+		t.Error("Spectators see the showdown after the delay", view)
+	}
+	if players := game.SpectatorPlayers(); players[0].Chips+players[1].Chips+players[2].Chips != 300 {
+		t.Error(players)
+	}
+
+	if err := game.SetSpectatorRules(SpectatorRules{ShowdownDelay: -time.Second}); err == nil {
+		t.Error("Delay can't be negative")
+	}
+}
+
+func TestSpectators(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 19, 0, 0, 0, time.UTC)
+	clock := ClockFunc(func() time.Time { return now })
+	game := newTestGame(t, GameOptions{Clock: clock}, 0, 1)
+
+	for _, token := range []string{"alice", "bob"} {
+		if err := game.AddSpectator(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := game.Watch("carol"); !errors.Is(err, ErrNotSpectating) {
+		t.Error("Carol never started spectating", err)
+	}
+
+	// Bob stops looking at the game, so only Alice is still watching
+	now = now.Add(SpectatorTimeout / 2)
+	if err := game.Watch("alice"); err != nil || game.Spectators() != 2 {
+		t.Error(err, game.Spectators())
+	}
+	now = now.Add(SpectatorTimeout / 2)
+	if game.Spectators() != 1 {
+		t.Error(game.Spectators())
+	}
+	if err := game.Watch("bob"); !errors.Is(err, ErrNotSpectating) {
+		t.Error("Bob has to spectate again", err)
+	}
+
+	if err := game.SetSpectatorRules(SpectatorRules{Disabled: true}); err != nil || !game.SpectatorRules().Disabled {
+		t.Fatal(err)
+	}
+	if err := game.Watch("alice"); !errors.Is(err, ErrNotSpectating) || game.Spectators() != 0 {
+		t.Error("Disabling spectators sends everyone away", err)
+	}
+	if err := game.AddSpectator("bob"); !errors.Is(err, ErrSpectatingDisabled) {
+		t.Error(err)
+	}
+}
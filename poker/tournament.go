@@ -0,0 +1,396 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BlindLevel is one level of a tournament's blind schedule. A level ends once its Duration
+// has passed or its Hands have been played, whichever is set and comes first. The last
+// level lasts until the end of the tournament
+type BlindLevel struct {
+	Blinds Blinds `json:"blinds"`
+	// Limits are the bet sizes for limit games during the level. Bet sizes left as 0 are based
+	// on the big blind, like in a new game
+	Limits   BettingLimits `json:"limits"`
+	Duration time.Duration `json:"duration"`
+	Hands    int           `json:"hands"`
+	// Break is how long the break after this level lasts, 0 for no break
+	Break time.Duration `json:"break"`
+}
+
+// Finish is where a player finished in a tournament
+type Finish struct {
+	// Entrant is the id of the player in a multi-table tournament
+	Entrant int    `json:"entrant"`
+	Seat    int    `json:"seat"`
+	Name    string `json:"name"`
+	Place   int    `json:"place"`
+	// Hand is the number of the hand the player was knocked out in
+	Hand int `json:"hand"`
+}
+
+// TournamentOptions are the rules a tournament is created with
+type TournamentOptions struct {
+	// Game are the rules for the game played. The blinds come from the first level instead
+	Game   GameOptions
+	Levels []BlindLevel
+	// BuyIn is what each entrant pays into the prize pool
+	BuyIn int
+	// Payouts split up the prize pool. The standard payouts for the field size are used when
+	// no percentages are set
+	Payouts PayoutStructure
+	// RebuyLevels is how many levels rebuys and re-entries are allowed for, 0 for the whole
+	// tournament. The rebuy rules themselves are part of the game's rules
+	RebuyLevels  int
+	MaxReEntries int
+	AddOn        AddOn
+}
+
+// prizes gets the prize for each place paid from everyone's buy ins. Each re-entry counts as
+// another entrant when picking the standard payouts
+func (options *TournamentOptions) prizes(buyIns []BuyIns) ([]int, error) {
+	entries := 0
+	prizePool := 0
+	for _, paid := range buyIns {
+		entries += 1 + paid.ReEntries
+		prizePool += paid.Total
+	}
+
+	payouts := options.Payouts
+	if len(payouts.Percentages) == 0 {
+		payouts = StandardPayouts(entries)
+	}
+	return payouts.Prizes(prizePool)
+}
+
+// Tournament is a game where the blinds go up on a schedule, and players are knocked out
+// when they run out of chips until one player has them all
+type Tournament struct {
+	Game
+
+	options       TournamentOptions
+	schedule      blindSchedule
+	startingChips map[int]int
+	finishes      []Finish
+	lastHand      int
+}
+
+// blindSchedule tracks which level of the blind schedule is being played
+type blindSchedule struct {
+	levels       []BlindLevel
+	level        int
+	levelStarted time.Time
+	handsInLevel int
+	onBreak      bool
+	breakEnds    time.Time
+	started      bool
+	clock        Clock
+}
+
+// NewTournament creates a tournament with the specified rules
+func NewTournament(options TournamentOptions) (Tournament, error) {
+	game, err := options.newTable()
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	return Tournament{
+		Game:          game,
+		options:       options,
+		schedule:      blindSchedule{levels: options.Levels, clock: game.clock},
+		startingChips: make(map[int]int),
+	}, nil
+}
+
+// newTable validates the blind schedule and creates a game at the first level
+func (options *TournamentOptions) newTable() (Game, error) {
+	if len(options.Levels) == 0 {
+		return Game{}, fmt.Errorf("Tournament needs at least one blind level")
+	}
+	if options.BuyIn < 0 || options.RebuyLevels < 0 || options.MaxReEntries < Unlimited {
+		return Game{}, fmt.Errorf("Tournament cannot have a negative buy in or rebuy limits")
+	}
+	if options.AddOn.Chips < 0 || options.AddOn.Cost < 0 || options.AddOn.Level < 0 {
+		return Game{}, fmt.Errorf("Tournament cannot have a negative add-on")
+	}
+	if options.Game.Rake.Percent > 0 {
+		return Game{}, fmt.Errorf("Tournaments cannot have a rake")
+	}
+	if len(options.Payouts.Percentages) > 0 {
+		if err := options.Payouts.validate(); err != nil {
+			return Game{}, err
+		}
+	}
+	for i, level := range options.Levels {
+		if err := level.Blinds.validate(); err != nil {
+			return Game{}, fmt.Errorf("Level %d: %s", i+1, err)
+		}
+		if level.Duration < 0 || level.Hands < 0 || level.Break < 0 {
+			return Game{}, fmt.Errorf("Level %d cannot have a negative length or break", i+1)
+		}
+		if i < len(options.Levels)-1 && level.Duration == 0 && level.Hands == 0 {
+			return Game{}, fmt.Errorf("Level %d needs a duration or number of hands", i+1)
+		}
+	}
+
+	gameOptions := options.Game
+	gameOptions.Blinds = options.Levels[0].Blinds
+	gameOptions.Limits = levelLimits(options.Levels[0], options.Game.Limits)
+	game, err := NewGameWithOptions(gameOptions)
+	if err != nil {
+		return Game{}, err
+	}
+
+	// Players can't sit out of a tournament, so running out of time never sits them out
+	game.shotClock.SitOutAfter = Unlimited
+	return game, nil
+}
+
+// levelLimits gets the bet sizes for level, keeping the raise cap and spread from base
+// when the level doesn't set them
+func levelLimits(level BlindLevel, base BettingLimits) BettingLimits {
+	limits := level.Limits
+	if limits.RaiseCap == 0 {
+		limits.RaiseCap = base.RaiseCap
+	}
+	if limits.SpreadMax == 0 {
+		limits.SpreadMax = base.SpreadMax
+	}
+	limits.fillDefaults(level.Blinds.BigBlind)
+	return limits
+}
+
+// StartHand moves the blinds up if the level is over, and starts the next hand. Returns an
+// error during a break or once the tournament is over
+func (tournament *Tournament) StartHand() error {
+	if tournament.Over() {
+		return fmt.Errorf("Tournament is over")
+	}
+	if tournament.HandInProgress() {
+		return fmt.Errorf("Hand %d is still in progress", tournament.handsPlayed)
+	}
+
+	if err := tournament.schedule.startHand(&tournament.Game); err != nil {
+		return err
+	}
+
+	for seat, player := range tournament.players {
+		tournament.startingChips[seat] = player.Chips
+	}
+	if err := tournament.Game.StartHand(); err != nil {
+		return err
+	}
+
+	// Everyone can be all in from the blinds, so the hand may already be over
+	tournament.finishHand()
+	return nil
+}
+
+// Act takes an action for the player in seat, knocking out players once the hand is over
+func (tournament *Tournament) Act(seat int, action Action) error {
+	if err := tournament.Game.Act(seat, action); err != nil {
+		return err
+	}
+
+	tournament.finishHand()
+	return nil
+}
+
+// ChooseRuns is a vote for how many times to run the board, knocking out players once the
+// hand is over
+func (tournament *Tournament) ChooseRuns(seat int, runs int) error {
+	if err := tournament.Game.ChooseRuns(seat, runs); err != nil {
+		return err
+	}
+
+	tournament.finishHand()
+	return nil
+}
+
+// Levels gets the blind schedule
+func (tournament *Tournament) Levels() []BlindLevel {
+	return tournament.schedule.levels
+}
+
+// CurrentLevel gets the index of the blind level being played
+func (tournament *Tournament) CurrentLevel() int {
+	tournament.schedule.update()
+	tournament.schedule.apply(&tournament.Game)
+	return tournament.schedule.level
+}
+
+// OnBreak tells if the tournament is on break, and when the break ends
+func (tournament *Tournament) OnBreak() (bool, time.Time) {
+	tournament.schedule.update()
+	return tournament.schedule.onBreak, tournament.schedule.breakEnds
+}
+
+// Prizes gets the prize for each place paid, starting with first
+func (tournament *Tournament) Prizes() ([]int, error) {
+	buyIns := make([]BuyIns, 0, len(tournament.players))
+	for _, player := range tournament.players {
+		buyIns = append(buyIns, player.BuyIns)
+	}
+	return tournament.options.prizes(buyIns)
+}
+
+// Over tells if one player has won all of the chips
+func (tournament *Tournament) Over() bool {
+	return tournament.schedule.started && len(tournament.activeSeats()) < 2
+}
+
+// Standings gets the players who have finished, best place first
+func (tournament *Tournament) Standings() []Finish {
+	result := append([]Finish{}, tournament.finishes...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Place < result[j].Place
+	})
+	return result
+}
+
+// Place gets the finishing place of the player in seat. Returns false if they are still playing
+func (tournament *Tournament) Place(seat int) (int, bool) {
+	for _, finish := range tournament.finishes {
+		if finish.Seat == seat {
+			return finish.Place, true
+		}
+	}
+	return 0, false
+}
+
+// startHand starts the clock on the first hand, and moves game up to the current level.
+// Returns an error during a break. Every hand counts towards levels which last for a number
+// of hands, no matter which table it is played at
+func (schedule *blindSchedule) startHand(game *Game) error {
+	if !schedule.started {
+		schedule.started = true
+		schedule.levelStarted = schedule.clock.Now()
+	}
+
+	schedule.update()
+	if schedule.onBreak {
+		return fmt.Errorf("Tournament is on break until %s", schedule.breakEnds.Format(time.Kitchen))
+	}
+
+	schedule.apply(game)
+	if game.shotClock.RefillPerLevel && game.refilledLevel != schedule.level {
+		game.refilledLevel = schedule.level
+		game.refillTimeBanks()
+	}
+	schedule.handsInLevel++
+	return nil
+}
+
+// apply sets the blinds and limits of game for the current level, unless game is in the
+// middle of a hand
+func (schedule *blindSchedule) apply(game *Game) {
+	level := schedule.levels[schedule.level]
+	if !game.HandInProgress() && game.blinds != level.Blinds {
+		game.blinds = level.Blinds
+		game.limits = levelLimits(level, game.limits)
+	}
+}
+
+// update moves through every level and break which has ended
+func (schedule *blindSchedule) update() {
+	if !schedule.started {
+		return
+	}
+
+	now := schedule.clock.Now()
+	for schedule.level < len(schedule.levels)-1 {
+		if schedule.onBreak {
+			if now.Before(schedule.breakEnds) {
+				return
+			}
+			schedule.onBreak = false
+			schedule.startLevel(schedule.level+1, schedule.breakEnds)
+			continue
+		}
+
+		level := schedule.levels[schedule.level]
+		ended := now
+		timeUp := level.Duration > 0 && !now.Before(schedule.levelStarted.Add(level.Duration))
+		if timeUp {
+			ended = schedule.levelStarted.Add(level.Duration)
+		}
+		if !timeUp && (level.Hands == 0 || schedule.handsInLevel < level.Hands) {
+			return
+		}
+
+		if level.Break > 0 {
+			schedule.onBreak = true
+			schedule.breakEnds = ended.Add(level.Break)
+			continue
+		}
+		schedule.startLevel(schedule.level+1, ended)
+	}
+}
+
+func (schedule *blindSchedule) startLevel(index int, started time.Time) {
+	schedule.level = index
+	schedule.levelStarted = started
+	schedule.handsInLevel = 0
+}
+
+// knockoutPlaces gets the finishing places of players knocked out in the same hand, given the
+// chips they started the hand with and how many players are left. Bigger starting stacks
+// finish higher, and players who started with the same stack tie
+func knockoutPlaces(startingChips []int, remaining int) []int {
+	order := make([]int, len(startingChips))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return startingChips[order[i]] > startingChips[order[j]]
+	})
+
+	places := make([]int, len(startingChips))
+	for i, index := range order {
+		places[index] = remaining + i + 1
+		if i > 0 && startingChips[index] == startingChips[order[i-1]] {
+			places[index] = places[order[i-1]]
+		}
+	}
+	return places
+}
+
+// finishHand knocks out every player who ran out of chips in the hand which just ended.
+// Players knocked out in the same hand finish in order of the chips they started it with
+func (tournament *Tournament) finishHand() {
+	if tournament.HandInProgress() || tournament.lastHand == tournament.handsPlayed {
+		return
+	}
+	tournament.lastHand = tournament.handsPlayed
+
+	busted := make([]int, 0)
+	starting := make([]int, 0)
+	for _, seat := range tournament.hand.seats {
+		if tournament.players[seat].Chips == 0 {
+			busted = append(busted, seat)
+			starting = append(starting, tournament.startingChips[seat])
+		}
+	}
+
+	remaining := len(tournament.activeSeats())
+	for i, place := range knockoutPlaces(starting, remaining) {
+		tournament.finishes = append(tournament.finishes, Finish{
+			Seat:  busted[i],
+			Name:  tournament.players[busted[i]].Name,
+			Place: place,
+			Hand:  tournament.handsPlayed,
+		})
+	}
+
+	if remaining == 1 {
+		winner := tournament.activeSeats()[0]
+		tournament.finishes = append(tournament.finishes, Finish{
+			Seat:  winner,
+			Name:  tournament.players[winner].Name,
+			Place: 1,
+			Hand:  tournament.handsPlayed,
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package poker
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestTournament(t *testing.T, levels []BlindLevel, seats ...int) (*Tournament, *time.Time) {
+	tournament, err := NewTournament(TournamentOptions{Game: GameOptions{StarterChips: 100}, Levels: levels})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2020, time.January, 1, 19, 0, 0, 0, time.UTC)
+	tournament.schedule.clock = ClockFunc(func() time.Time { return now })
+	for _, seat := range seats {
+		if _, err := tournament.AddPlayer("player", seat); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &tournament, &now
+}
+
+func foldTournamentHand(t *testing.T, tournament *Tournament) {
+	t.Helper()
+	if err := tournament.StartHand(); err != nil {
+		t.Fatal(err)
+	}
+	for tournament.HandInProgress() {
+		if err := tournament.Act(tournament.hand.toAct, Action{Type: Fold}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTournamentLevelsByHands(t *testing.T) {
+	tournament, _ := newTestTournament(t, []BlindLevel{
+		{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}, Hands: 2},
+		{Blinds: Blinds{SmallBlind: 10, BigBlind: 20, Ante: 5}},
+	}, 0, 1, 2)
+
+	foldTournamentHand(t, tournament)
+	foldTournamentHand(t, tournament)
+	if tournament.CurrentLevel() != 1 || tournament.BlindSize() != 20 {
+		t.Error("Blinds go up after two hands")
+	}
+
+	foldTournamentHand(t, tournament)
+	if tournament.History()[2].Actions[0].Action.Type != Ante || tournament.Limits().SmallBet != 20 {
+		t.Error(tournament.History()[2].Actions)
+	}
+}
+
+func TestTournamentLevelsByTime(t *testing.T) {
+	tournament, now := newTestTournament(t, []BlindLevel{
+		{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}, Duration: 20 * time.Minute, Break: 10 * time.Minute},
+		{Blinds: Blinds{SmallBlind: 10, BigBlind: 20}, Duration: 20 * time.Minute},
+		{Blinds: Blinds{SmallBlind: 20, BigBlind: 40}},
+	}, 0, 1, 2)
+
+	foldTournamentHand(t, tournament)
+	*now = now.Add(25 * time.Minute)
+	if onBreak, ends := tournament.OnBreak(); !onBreak || ends != now.Add(5*time.Minute) {
+		t.Error("Break starts when the first level ends")
+	}
+	if err := tournament.StartHand(); err == nil {
+		t.Error("No hands during the break")
+	}
+
+	*now = now.Add(10 * time.Minute)
+	foldTournamentHand(t, tournament)
+	if tournament.CurrentLevel() != 1 || tournament.BlindSize() != 20 {
+		t.Error("Next level starts after the break")
+	}
+
+	*now = now.Add(15 * time.Minute)
+	if tournament.CurrentLevel() != 2 {
+		t.Error("Break time doesn't count towards the level")
+	}
+}
+
+func TestTournamentEliminations(t *testing.T) {
+	tournament, _ := newTestTournament(t, []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}}}, 0, 1, 2)
+	setChips(&tournament.Game, 0, 200)
+	setChips(&tournament.Game, 1, 50)
+	if err := tournament.StartHand(); err != nil {
+		t.Fatal(err)
+	}
+
+	tournament.hand.down[0] = buildCards([]string{"AS", "AH"})
+	tournament.hand.down[1] = buildCards([]string{"KS", "KH"})
+	tournament.hand.down[2] = buildCards([]string{"QS", "QH"})
+	stackDeck(&tournament.Game, buildCards([]string{"2C", "7D", "9C", "3H", "4D"}))
+
+	if err := tournament.Act(0, Action{Type: Raise, Amount: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tournament.Act(1, Action{Type: Call}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tournament.Act(2, Action{Type: Call}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !tournament.Over() {
+		t.Fatal("Seat 0 won every chip")
+	}
+	standings := tournament.Standings()
+	if len(standings) != 3 || standings[0].Seat != 0 || standings[1].Seat != 2 || standings[2].Seat != 1 {
+		t.Error("Bigger starting stack finishes higher", standings)
+	}
+	if place, ok := tournament.Place(1); !ok || place != 3 {
+		t.Error()
+	}
+	if err := tournament.StartHand(); err == nil {
+		t.Error("No hands once the tournament is over")
+	}
+}
+
+func TestInvalidTournament(t *testing.T) {
+	if _, err := NewTournament(TournamentOptions{Game: GameOptions{StarterChips: 100}}); err == nil {
+		t.Error("Need a level")
+	}
+
+	levels := []BlindLevel{{Blinds: Blinds{SmallBlind: 5, BigBlind: 10}}, {Blinds: Blinds{SmallBlind: 10, BigBlind: 20}}}
+	if _, err := NewTournament(TournamentOptions{Game: GameOptions{StarterChips: 100}, Levels: levels}); err == nil {
+		t.Error("Only the last level can last forever")
+	}
+}
@@ -0,0 +1,177 @@
+package poker
+
+import (
+	"fmt"
+)
+
+// Variant is the kind of poker dealt in a hand, i.e. Hold'em or Razz
+type Variant int
+
+const (
+	HoldEm Variant = iota
+	Omaha
+	OmahaHiLo
+	Razz
+	Stud
+	StudHiLo
+)
+
+var variantNames = [...]string{
+	"holdem",
+	"omaha",
+	"omaha-hi-lo",
+	"razz",
+	"stud",
+	"stud-hi-lo",
+}
+
+func (v Variant) String() string {
+	return variantNames[v]
+}
+
+// MarshalText lets a Variant be serialized by its name
+func (v Variant) MarshalText() ([]byte, error) {
+	if v < HoldEm || int(v) >= len(variantNames) {
+		return nil, fmt.Errorf("Unknown variant %d", v)
+	}
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses a Variant from its name
+func (v *Variant) UnmarshalText(text []byte) error {
+	for i, name := range variantNames {
+		if name == string(text) {
+			*v = Variant(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown variant %q", string(text))
+}
+
+// BettingStructure limits how much a player can bet or raise
+type BettingStructure int
+
+const (
+	NoLimit BettingStructure = iota
+	PotLimit
+	FixedLimit
+	SpreadLimit
+)
+
+var bettingStructureNames = [...]string{
+	"no-limit",
+	"pot-limit",
+	"fixed-limit",
+	"spread-limit",
+}
+
+func (b BettingStructure) String() string {
+	return bettingStructureNames[b]
+}
+
+// MarshalText lets a BettingStructure be serialized by its name
+func (b BettingStructure) MarshalText() ([]byte, error) {
+	if b < NoLimit || int(b) >= len(bettingStructureNames) {
+		return nil, fmt.Errorf("Unknown betting structure %d", b)
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText parses a BettingStructure from its name
+func (b *BettingStructure) UnmarshalText(text []byte) error {
+	for i, name := range bettingStructureNames {
+		if name == string(text) {
+			*b = BettingStructure(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown betting structure %q", string(text))
+}
+
+// GameType is a variant paired with how it is bet, i.e. pot limit Omaha
+type GameType struct {
+	Variant Variant          `json:"variant"`
+	Betting BettingStructure `json:"betting"`
+}
+
+func (g GameType) String() string {
+	return fmt.Sprintf("%s %s", g.Betting, g.Variant)
+}
+
+// Rotation decides which game is played each hand. The zero value plays no limit Hold'em forever
+type Rotation struct {
+	// Games are played in order, wrapping around to the first. In dealer's choice these are
+	// the games the dealer can pick from
+	Games []GameType `json:"games"`
+	// HandsPerGame is the number of hands played before switching games
+	HandsPerGame int `json:"handsPerGame"`
+	// Orbits is the number of times the button goes around the table before switching games.
+	// Takes precedence over HandsPerGame
+	Orbits int `json:"orbits"`
+	// DealersChoice has the player on the button pick the next game, rather than following
+	// the order of Games. If neither HandsPerGame or Orbits are set, the choice lasts one orbit
+	DealersChoice bool `json:"dealersChoice"`
+}
+
+// HORSE is the classic rotation of limit Hold'em, Omaha Hi/Lo, Razz, Stud and Stud Hi/Lo
+func HORSE(handsPerGame int) Rotation {
+	return Rotation{
+		Games: []GameType{
+			{Variant: HoldEm, Betting: FixedLimit},
+			{Variant: OmahaHiLo, Betting: FixedLimit},
+			{Variant: Razz, Betting: FixedLimit},
+			{Variant: Stud, Betting: FixedLimit},
+			{Variant: StudHiLo, Betting: FixedLimit},
+		},
+		HandsPerGame: handsPerGame,
+	}
+}
+
+func (rotation *Rotation) validate() error {
+	if rotation.HandsPerGame < 0 || rotation.Orbits < 0 {
+		return fmt.Errorf("Rotation needs a non-negative number of hands and orbits per game")
+	}
+
+	if len(rotation.Games) > 1 && !rotation.DealersChoice && rotation.HandsPerGame == 0 && rotation.Orbits == 0 {
+		return fmt.Errorf("Rotation with multiple games needs to switch after some hands or orbits")
+	}
+
+	for _, gameType := range rotation.Games {
+		if _, err := gameType.Variant.MarshalText(); err != nil {
+			return err
+		}
+		if _, err := gameType.Betting.MarshalText(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gameLength is the number of hands each game is played for, given how many players are in
+// an orbit. Returns 0 if the game is played forever
+func (rotation *Rotation) gameLength(orbitSize int) int {
+	orbits := rotation.Orbits
+	if orbits == 0 && rotation.HandsPerGame == 0 && rotation.DealersChoice {
+		orbits = 1
+	}
+
+	if orbits > 0 {
+		return orbits * orbitSize
+	}
+	return rotation.HandsPerGame
+}
+
+func (rotation *Rotation) allows(gameType GameType) bool {
+	if len(rotation.Games) == 0 {
+		return true
+	}
+
+	for _, allowed := range rotation.Games {
+		if allowed == gameType {
+			return true
+		}
+	}
+
+	return false
+}
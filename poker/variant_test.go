@@ -0,0 +1,43 @@
+package poker
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGameTypeJSON(t *testing.T) {
+	gameType := GameType{Variant: OmahaHiLo, Betting: PotLimit}
+	encoded, err := json.Marshal(gameType)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(encoded) != `{"variant":"omaha-hi-lo","betting":"pot-limit"}` {
+		t.Error(string(encoded))
+	}
+
+	decoded := GameType{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded != gameType {
+		t.Error()
+	}
+
+	if err := json.Unmarshal([]byte(`{"variant":"badugi"}`), &decoded); err == nil {
+		t.Error()
+	}
+}
+
+func TestRotationValidate(t *testing.T) {
+	horse := HORSE(0)
+	if horse.validate() == nil {
+		t.Error("Multiple games need to switch at some point")
+	}
+
+	horse = HORSE(8)
+	if horse.validate() != nil {
+		t.Error()
+	}
+
+	rotation := Rotation{Games: []GameType{{Variant: Variant(42)}}}
+	if rotation.validate() == nil {
+		t.Error()
+	}
+}
@@ -0,0 +1,112 @@
+package poker
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSeatOfferTimeout is how long someone on the waitlist has to take an open seat, unless
+// the game sets its own timeout
+const DefaultSeatOfferTimeout time.Duration = 2 * time.Minute
+
+// SeatOffer is an open seat held for the next person on the waitlist. It is a reservation, so
+// they take it by sitting down in the seat with their waitlist secret before it expires
+type SeatOffer struct {
+	Seat    int       `json:"seat"`
+	Name    string    `json:"name"`
+	Expires time.Time `json:"expires"`
+}
+
+// waitlistEntry is someone waiting for a seat. The secret is what leaving the waitlist or
+// sitting in the seat offered to them takes
+type waitlistEntry struct {
+	name   string
+	secret int
+}
+
+// JoinWaitlist puts name at the back of the line for a seat. Returns the secret for leaving the
+// waitlist, which is also the claim for the seat they get offered
+func (game *Game) JoinWaitlist(name string) (int, error) {
+	if name == "" {
+		return 0, fmt.Errorf("Waitlist needs a name")
+	}
+	if indexOfName(game.waitlist, name) != -1 {
+		return 0, fmt.Errorf("%s is already on the waitlist", name)
+	}
+	if _, ok := game.offeredSeat(name); ok {
+		return 0, fmt.Errorf("%s already has a seat offered to them", name)
+	}
+
+	entry := waitlistEntry{name: name, secret: newSecret()}
+	game.waitlist = append(game.waitlist, entry)
+	return entry.secret, nil
+}
+
+// LeaveWaitlist takes name out of the line, or turns down the seat offered to them so it can
+// go to the next person. It takes the secret name joined the waitlist with
+func (game *Game) LeaveWaitlist(name string, secret int) error {
+	if seat, ok := game.offeredSeat(name); ok {
+		if game.reservations[seat].claim != secret {
+			return errorOf(ErrWrongClaim, "Wrong secret for %s on the waitlist", name)
+		}
+		delete(game.reservations, seat)
+		return nil
+	}
+
+	index := indexOfName(game.waitlist, name)
+	if index == -1 {
+		return fmt.Errorf("%s is not on the waitlist", name)
+	}
+	if game.waitlist[index].secret != secret {
+		return errorOf(ErrWrongClaim, "Wrong secret for %s on the waitlist", name)
+	}
+	game.waitlist = append(game.waitlist[:index:index], game.waitlist[index+1:]...)
+	return nil
+}
+
+// Waitlist gets the names waiting for a seat, in the order they will be offered one
+func (game *Game) Waitlist() []string {
+	result := make([]string, 0, len(game.waitlist))
+	for _, entry := range game.waitlist {
+		result = append(result, entry.name)
+	}
+	return result
+}
+
+// OfferSeats offers every open seat to the people at the front of the waitlist. Seats whose
+// offer ran out are offered to the next person in line, since the person who let it expire
+// has left the waitlist. Returns the offers which were just made
+func (game *Game) OfferSeats() []SeatOffer {
+	result := make([]SeatOffer, 0)
+	for _, seat := range game.EmptySeats() {
+		if len(game.waitlist) == 0 {
+			break
+		}
+
+		entry := game.waitlist[0]
+		game.waitlist = game.waitlist[1:]
+		reservation := Reservation{Name: entry.name, Expires: game.clock.Now().Add(game.offerTimeout), Waitlist: true, claim: entry.secret}
+		game.reservations[seat] = reservation
+		result = append(result, SeatOffer{Seat: seat, Name: entry.name, Expires: reservation.Expires})
+	}
+	return result
+}
+
+// offeredSeat finds the seat offered to name from the waitlist
+func (game *Game) offeredSeat(name string) (int, bool) {
+	for seat := range game.reservations {
+		if reservation, ok := game.reservation(seat); ok && reservation.Waitlist && reservation.Name == name {
+			return seat, true
+		}
+	}
+	return 0, false
+}
+
+func indexOfName(entries []waitlistEntry, name string) int {
+	for i, entry := range entries {
+		if entry.name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,76 @@
+package poker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitlist(t *testing.T) {
+	game := newTestGame(t, GameOptions{SeatOfferTimeout: time.Minute}, 0, 1, 2, 3, 4, 5, 6, 7)
+	now := time.Now()
+	game.clock = ClockFunc(func() time.Time { return now })
+
+	if _, err := game.AddPlayer("alice", 0); err == nil {
+		t.Error("Game is full")
+	}
+	game.JoinWaitlist("alice")
+	bobSecret, _ := game.JoinWaitlist("bob")
+	if _, err := game.JoinWaitlist("alice"); err == nil {
+		t.Error("Alice is already waiting")
+	}
+	if offers := game.OfferSeats(); len(offers) != 0 {
+		t.Error("No seats are open")
+	}
+
+	game.LeaveTable(3)
+	offers := game.OfferSeats()
+	if len(offers) != 1 || offers[0].Seat != 3 || offers[0].Name != "alice" || len(game.EmptySeats()) != 0 {
+		t.Fatal(offers)
+	}
+	if _, err := game.ClaimSeat("bob", 3, bobSecret); err == nil {
+		t.Error("Seat 3 is offered to alice")
+	}
+
+	// Alice doesn't take the seat in time, so it goes to bob
+	now = now.Add(time.Minute)
+	offers = game.OfferSeats()
+	if len(offers) != 1 || offers[0].Name != "bob" || len(game.Waitlist()) != 0 {
+		t.Fatal(offers)
+	}
+	if _, err := game.AddPlayer("bob", 3); err == nil {
+		t.Error("Taking the offered seat takes bob's secret, not the name")
+	}
+	if _, err := game.ClaimSeat("bob", 3, bobSecret); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeclineSeatOffer(t *testing.T) {
+	game := newTestGame(t, GameOptions{}, 0, 1, 2, 3, 4, 5, 6)
+	aliceSecret, _ := game.JoinWaitlist("alice")
+	bobSecret, _ := game.JoinWaitlist("bob")
+	game.JoinWaitlist("carol")
+	if err := game.LeaveWaitlist("bob", aliceSecret); !errors.Is(err, ErrWrongClaim) {
+		t.Error("Leaving takes bob's own secret", err)
+	}
+	if err := game.LeaveWaitlist("bob", bobSecret); err != nil {
+		t.Fatal(err)
+	}
+
+	if offers := game.OfferSeats(); len(offers) != 1 || offers[0].Name != "alice" {
+		t.Fatal(offers)
+	}
+	if err := game.LeaveWaitlist("alice", bobSecret); !errors.Is(err, ErrWrongClaim) {
+		t.Error("Declining takes alice's own secret", err)
+	}
+	if err := game.LeaveWaitlist("alice", aliceSecret); err != nil {
+		t.Fatal(err)
+	}
+	if offers := game.OfferSeats(); len(offers) != 1 || offers[0].Name != "carol" || offers[0].Seat != 7 {
+		t.Error(offers)
+	}
+	if err := game.LeaveWaitlist("alice", aliceSecret); err == nil {
+		t.Error("Alice already left the waitlist")
+	}
+}
@@ -127,8 +127,8 @@ async function getGameState(gameID, passphrase) {
     let game = await r.json();
     return game;
   } 
-  let error = await r.text();
-  return {error: error};
+  let error = await r.json();
+  return {error: error.message};
 }
 
 class GameLogin extends React.Component {
@@ -176,63 +176,33 @@ class GameLogin extends React.Component {
 class PokerTable extends React.Component {
   constructor(props) {
     super(props);
-    this.seatStyles = [
-      {
-        "position": "absolute",
-        "left": "30%",
-        "top": "5%",
-      },
-      {
-        "position": "absolute",
-        "right": "30%",
-        "top": "5%",
-      },
-      {
-        "position": "absolute",
-        "right": "3%",
-        "top": "25%",
-      },
-      {
-        "position": "absolute",
-        "right": "3%",
-        "top": "55%",
-      },
-      {
-        "position": "absolute",
-        "right": "30%",
-        "bottom": "5%",
-      },
-      {
-        "position": "absolute",
-        "left": "30%",
-        "bottom": "5%",
-      },
-      {
-        "position": "absolute",
-        "left": "3%",
-        "top": "55%",
-      },
-      {
-        "position": "absolute",
-        "left": "3%",
-        "top": "25%",
-      },
-    ];
-
-    this.numSeats = 8;
-
     this.state = {
+      gameID: props.gameID,
       emptySeats: [],
       players: {},
+      tableSize: 8,
       passphrase: props.initialPassphrase,
       blindSize: 0,
       error: '',
     }
   }
 
+  // Seats go clockwise around the table starting at the top left, spread out evenly for any
+  // table size from heads up to 10-max
+  seatStyle(seat) {
+    const tableSize = this.state.tableSize;
+    const angle = Math.PI * (1.5 - 1 / tableSize + 2 * seat / tableSize);
+    return {
+      "position": "absolute",
+      "left": `${50 + 45 * Math.cos(angle)}%`,
+      "top": `${50 + 42 * Math.sin(angle)}%`,
+      "transform": "translate(-50%, -50%)",
+    };
+  }
+
   renderPlayer(seat, player) {
     return (
-      <div style={this.seatStyles[seat]}>
+      <div style={this.seatStyle(seat)}>
         {player.render()}
       </div>
     )
@@ -242,6 +212,7 @@ class PokerTable extends React.Component {
     this.setState({
       emptySeats: gameState.emptySeats,
       players: gameState.players,
+      tableSize: gameState.tableSize,
       blindSize: gameState.blindSize,
       passphrase: passphrase,
     })
@@ -302,6 +273,7 @@ class CreateGameComponent extends React.Component {
       passphrase: '',
       starterChips: '',
       blindSize: '',
+      tableSize: '8',
       error: '',
     }
   }
@@ -325,11 +297,12 @@ class CreateGameComponent extends React.Component {
       passphrase: passphrase,
       starterChips: starterChips,
       blindSize: blindSize,
+      tableSize: Number(this.state.tableSize),
     }).then(response => {
       if (response.ok) {
         response.json().then(game => {this.props.onGameCreateSuccess(game, passphrase)})
       } else {
-        response.text().then(err => this.setState({error: err}))
+        response.json().then(err => this.setState({error: err.message}))
       }
     }).catch(error => {
       this.setState({error: 'An error has occurred'});
@@ -356,6 +329,10 @@ class CreateGameComponent extends React.Component {
     this.setState({blindSize: event.target.value});
   }
 
+  handleTableSizeChange = (event) => {
+    this.setState({tableSize: event.target.value});
+  }
+
   render() {
     return (
       <div style={{width: '400px', position: 'absolute'}} class="center">
@@ -372,6 +349,16 @@ class CreateGameComponent extends React.Component {
             Blind Size:
             <input type="text" value={this.state.blindSize} onChange={this.handleBindSizeChange}></input>
           </label>
+          <label>
+            Table Size:
+            <select value={this.state.tableSize} onChange={this.handleTableSizeChange}>
+              <option value="2">Heads Up</option>
+              <option value="6">6-Max</option>
+              <option value="8">8-Max</option>
+              <option value="9">9-Max</option>
+              <option value="10">10-Max</option>
+            </select>
+          </label>
           <input type="submit" value="Create"/>
           <text style={{color: 'red'}} >{this.state.error}</text>
         </form>
@@ -384,6 +371,7 @@ const Pages = Object.freeze({
   MAIN: "main",
   CREATE_GAME: "create_game",
   GAME: "game",
+  JOIN: "join",
 })
 
 class MainComponent extends React.Component {
@@ -394,17 +382,22 @@ class MainComponent extends React.Component {
 
     var page = null;
     var url = null;
-    var gameID = NaN;
+    var gameID = null;
+    var invite = null;
 
     if (pathname === "/create") {
       page = Pages.CREATE_GAME
       url = '/create'
     } else if (pathname === "/game") {
       const params = new URLSearchParams(query);
-      gameID = parseInt(params.get('gameID'));
-      if (!isNaN(gameID)) {
+      gameID = params.get('gameID');
+      invite = params.get('invite');
+      if (gameID) {
         page = Pages.GAME;
-        url = `/game?gameID=${gameID}`;
+        url = `/game?gameID=${encodeURIComponent(gameID)}`;
+      } else if (invite) {
+        page = Pages.JOIN;
+        url = `/game?invite=${encodeURIComponent(invite)}`;
       } else {
         page = Pages.MAIN;
         url = ''
@@ -419,7 +412,10 @@ class MainComponent extends React.Component {
       page: page,
       // Only valid if page is GAME
       gameID: gameID,
+      // Only valid if page is JOIN
+      invite: invite,
       passphrase: '',
+      error: '',
     }
   }
 
@@ -434,6 +430,27 @@ class MainComponent extends React.Component {
 
   componentDidMount() {
     window.addEventListener("popstate", this.handlePopState);
+    if (this.state.page === Pages.JOIN) {
+      this.joinGame(this.state.invite);
+    }
+  }
+
+  // Invite links carry the game, and the invite stands in for its passphrase, so whoever has one goes
+  // straight to the table
+  joinGame = async (invite) => {
+    try {
+      let r = await makeRequest('/api/v1/game/join', 'POST', {invite: invite});
+      let join = await r.json();
+      if (!r.ok) {
+        this.setState({error: join.message});
+        return;
+      }
+
+      window.history.replaceState({page: Pages.GAME}, '', `/game?gameID=${encodeURIComponent(join.game.gameID)}`)
+      this.setState({ page: Pages.GAME, gameID: join.game.gameID, passphrase: invite })
+    } catch (error) {
+      this.setState({error: 'An error has occurred'});
+    }
   }
 
   componentWillUnmount() {
@@ -441,7 +458,7 @@ class MainComponent extends React.Component {
   }
 
   handleCreateGameSuccess = (game, passphrase) => {
-    window.history.pushState({page: Pages.GAME}, '', `/game?gameID=${game.gameID}`)
+    window.history.pushState({page: Pages.GAME}, '', `/game?gameID=${encodeURIComponent(game.gameID)}`)
     this.setState({ page: Pages.GAME, gameID: game.gameID, passphrase: passphrase})
   }
 
@@ -458,11 +475,18 @@ class MainComponent extends React.Component {
           onGameCreateSuccess={this.handleCreateGameSuccess}
         />
       );
+    } else if (this.state.page === Pages.JOIN) {
+      return (
+        <div style={{width: '400px', position: 'absolute'}} class="center">
+          <text style={{color: 'red'}}>{this.state.error}</text>
+          {this.state.error.length === 0 && <text>Joining game...</text>}
+        </div>
+      );
     } else if (this.state.page === Pages.MAIN) {
       return (
         <div style={{width: '400px', position: 'absolute'}} class="center">
           <button class="main-button" type="button" onClick={this.handleCreateClick}>Create Game</button>
-          <text>Want to join a game? Just paste the link your friend sends you</text>
+          <text>Want to join a game? Just open the invite link your friend sends you</text>
         </div>
       );
     } else {